@@ -0,0 +1,26 @@
+package brauser
+
+import "encoding/base64"
+
+// SetBearerToken makes the client send "Authorization: Bearer <token>" with
+// every request. A request carrying its own Authorization header overrides it.
+func (w *WebClient) SetBearerToken(token string) {
+	w.setAuth("Bearer " + token)
+}
+
+// SetBasicAuth makes the client send HTTP basic auth credentials with every
+// request. A request carrying its own Authorization header overrides them.
+func (w *WebClient) SetBasicAuth(user, pass string) {
+	w.setAuth("Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass)))
+}
+
+// ClearAuth removes credentials stored by SetBearerToken or SetBasicAuth.
+func (w *WebClient) ClearAuth() {
+	w.setAuth("")
+}
+
+func (w *WebClient) setAuth(v string) {
+	w.state.mu.Lock()
+	w.state.auth = v
+	w.state.mu.Unlock()
+}
@@ -0,0 +1,59 @@
+package brauser
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// SetBaseURL makes request paths relative to base. A path is appended to the
+// base path, so with a base of "https://api.example.com/v1" both "users" and
+// "/users" resolve to "https://api.example.com/v1/users", and "?page=2"
+// resolves to the base itself with that query. Absolute URLs are sent
+// unchanged. An empty base removes it again.
+func (w *WebClient) SetBaseURL(base string) error {
+	var u *url.URL
+	if base != "" {
+		var err error
+		if u, err = url.Parse(base); err != nil {
+			return err
+		}
+		if !u.IsAbs() || u.Host == "" {
+			return errors.New("brauser: base URL must be absolute: " + base)
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			u.Path += "/"
+			if u.RawPath != "" {
+				u.RawPath += "/"
+			}
+		}
+	}
+
+	w.state.mu.Lock()
+	w.state.baseURL = u
+	w.state.mu.Unlock()
+	return nil
+}
+
+// resolve returns path resolved against the base URL, if one is set.
+func (w *WebClient) resolve(path string) (string, error) {
+	w.state.mu.RLock()
+	base := w.state.baseURL
+	w.state.mu.RUnlock()
+	if base == nil {
+		return path, nil
+	}
+
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	if ref.IsAbs() {
+		return path, nil
+	}
+	if ref.Host == "" {
+		ref.Path = strings.TrimPrefix(ref.Path, "/")
+		ref.RawPath = strings.TrimPrefix(ref.RawPath, "/")
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,64 @@
+package brauser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Result is the outcome of one request of GetAll.
+type Result struct {
+	Path string
+	Response
+	Err error
+}
+
+// GetAll fetches paths with at most concurrency requests in flight and
+// returns the results in the order of paths. Failures, and even panics, of
+// single requests are reported in their Result and don't affect the others.
+// The client's rate limit applies across all requests.
+func (w *WebClient) GetAll(paths []string, concurrency int) []Result {
+	return w.GetAllWithContext(context.Background(), paths, concurrency)
+}
+
+// GetAllWithContext is like GetAll, but requests not yet sent when ctx is
+// done fail with its error.
+func (w *WebClient) GetAllWithContext(ctx context.Context, paths []string, concurrency int) []Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]Result, len(paths))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(paths); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = w.getOne(ctx, paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+func (w *WebClient) getOne(ctx context.Context, path string) (r Result) {
+	r.Path = path
+	defer func() {
+		if p := recover(); p != nil {
+			r.Err = fmt.Errorf("brauser: panic fetching %s: %v", path, p)
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		r.Err = err
+		return
+	}
+	r.Response, r.Err = w.fetch(request{ctx: ctx, method: "GET", path: path})
+	return
+}
@@ -0,0 +1,164 @@
+package brauser
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func benchmarkGet(b *testing.B, o Options) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	w := CreateWebClient(o)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := w.Get(srv.URL, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	benchmarkGet(b, DefaultOptions())
+}
+
+func BenchmarkGetLogHeaders(b *testing.B) {
+	// LogHeaders without a logger must not cost anything either.
+	o := DefaultOptions()
+	o.LogHeaders = true
+	o.LogBodies = true
+	benchmarkGet(b, o)
+}
+
+func BenchmarkGetTraceTimings(b *testing.B) {
+	o := DefaultOptions()
+	o.TraceTimings = true
+	benchmarkGet(b, o)
+}
+
+func TestDisabledLogsDontAllocate(t *testing.T) {
+	w := CreateWebClient()
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if w.logsEnabled() {
+			w.logf("%s %s", req.Method, req.URL)
+		}
+		w.logRetry(req, 1, 0, resp, nil)
+	})
+	if allocs != 0 {
+		t.Errorf("%v allocations per request with logging off, want 0", allocs)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.SetCookie(rw, &http.Cookie{Name: "n", Value: r.URL.Query().Get("n")})
+		fmt.Fprint(rw, r.URL.Query().Get("n"))
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.LatencySamples = 10
+	w := CreateWebClient(o)
+	if err := w.SetBaseURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			want := fmt.Sprint(i)
+			resp, err := w.Send(context.Background(), "GET", "/", Params{Query: map[string]string{"n": want}}, nil)
+			if err != nil || string(resp.Body) != want {
+				t.Errorf("request %d: got %q, %v", i, resp.Body, err)
+			}
+			// Settings and state may change while requests are in flight.
+			switch i % 4 {
+			case 0:
+				w.SetDefaultHeader("X-Request", want)
+			case 1:
+				w.SetBearerToken(want)
+			case 2:
+				w.LastStatus()
+				w.LastHeaders()
+			case 3:
+				w.Cookies(srv.URL)
+				w.LatencyPercentile(50)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := w.LastStatus(); got != http.StatusOK {
+		t.Errorf("LastStatus = %d, want 200", got)
+	}
+}
+
+func TestPartialOptionsKeepDefaults(t *testing.T) {
+	d := DefaultOptions()
+	w := CreateWebClient(Options{Verbose: true})
+
+	if w.cl.Timeout != d.Timeout {
+		t.Errorf("client timeout %v, want %v", w.cl.Timeout, d.Timeout)
+	}
+	tr, ok := w.cl.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T", w.cl.Transport)
+	}
+	if tr.TLSHandshakeTimeout != d.TlsHandshakeTimeout || tr.IdleConnTimeout != d.IdleConnTimeout || tr.MaxIdleConnsPerHost != d.MaxIdleConnsPerHost {
+		t.Errorf("transport lost defaults: TLS handshake %v, idle %v, idle per host %d", tr.TLSHandshakeTimeout, tr.IdleConnTimeout, tr.MaxIdleConnsPerHost)
+	}
+	o := w.options
+	if o.DialTimeout != d.DialTimeout || o.Tries != d.Tries || o.UserAgent != d.UserAgent || !o.Verbose {
+		t.Errorf("options not filled in: %+v", o)
+	}
+
+	// Negative values still disable a timeout.
+	w = CreateWebClient(Options{Timeout: -1})
+	if w.cl.Timeout != 0 {
+		t.Errorf("client timeout %v with Timeout -1, want none", w.cl.Timeout)
+	}
+}
+
+func TestGetWithBody(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.Method+" "+string(b))
+		first := len(bodies) == 1
+		mu.Unlock()
+		if first {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.RetryBaseDelay = time.Millisecond
+	w := CreateWebClient(o)
+	if _, err := w.CustomRequest("GET", srv.URL, nil, strings.NewReader(`{"q":"x"}`)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`GET {"q":"x"}`, `GET {"q":"x"}`}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("server got %q, want the body on both attempts", bodies)
+	}
+}
@@ -0,0 +1,99 @@
+package brauser
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultBreakerCooldown = 30 * time.Second
+
+// breaker is a simple circuit breaker. After threshold consecutive failed
+// requests it rejects requests for cooldown, then lets a single probe request
+// through. A successful probe closes the circuit again, a failed one reopens
+// it for another cooldown.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// newBreaker returns nil, which disables circuit breaking, unless
+// o.BreakerThreshold is positive.
+func newBreaker(o Options, clk clock) *breaker {
+	if o.BreakerThreshold <= 0 {
+		return nil
+	}
+	cooldown := o.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	return &breaker{threshold: o.BreakerThreshold, cooldown: cooldown, clock: clk}
+}
+
+// allow reports whether a request may be sent now.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.clock.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *breaker) record(ok bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.clock.Now().Add(b.cooldown)
+	}
+}
+
+// release ends an allowed request without counting it either way, e.g.
+// because the caller cancelled it.
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) healthy() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures < b.threshold
+}
+
+// Healthy reports whether the circuit breaker is closed, i.e. requests are
+// being sent normally. It is always true when Options.BreakerThreshold is 0.
+func (w *WebClient) Healthy() bool {
+	return w.breaker.healthy()
+}
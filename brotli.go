@@ -0,0 +1,19 @@
+//go:build !brauser_nobrotli
+// +build !brauser_nobrotli
+
+package brauser
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Brotli support can be compiled out with the brauser_nobrotli build tag,
+// in which case "br" encoded bodies are returned as received.
+func init() {
+	newBrotliReader = func(r io.Reader) io.ReadCloser {
+		return ioutil.NopCloser(brotli.NewReader(r))
+	}
+}
@@ -0,0 +1,102 @@
+package brauser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestBuilder assembles a request step by step, as an alternative to the
+// positional arguments of Send:
+//
+//	resp, err := w.Request().Method("PUT").URL(u).Header("If-Match", etag).Body(r).Send()
+//
+// The setters return the builder so calls can be chained. A builder must not
+// be used concurrently.
+type RequestBuilder struct {
+	w  *WebClient
+	rq request
+}
+
+// Request starts building a GET request without context, headers or body.
+func (w *WebClient) Request() *RequestBuilder {
+	return &RequestBuilder{w: w, rq: request{ctx: context.Background(), method: "GET"}}
+}
+
+// Method sets the HTTP method.
+func (b *RequestBuilder) Method(method string) *RequestBuilder {
+	b.rq.method = method
+	return b
+}
+
+// URL sets the URL, which may be relative to the client's base URL.
+func (b *RequestBuilder) URL(u string) *RequestBuilder {
+	b.rq.path = u
+	return b
+}
+
+// Header sets a request header, replacing an earlier value of the same key.
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	if b.rq.header == nil {
+		b.rq.header = map[string]string{}
+	}
+	b.rq.header[key] = value
+	return b
+}
+
+// Query sets a query parameter, replacing an earlier value of the same key.
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	if b.rq.query == nil {
+		b.rq.query = map[string]string{}
+	}
+	b.rq.query[key] = value
+	return b
+}
+
+// AddQuery adds a query parameter, keeping earlier values of the same key.
+func (b *RequestBuilder) AddQuery(key, value string) *RequestBuilder {
+	if b.rq.values == nil {
+		b.rq.values = url.Values{}
+	}
+	b.rq.values.Add(key, value)
+	return b
+}
+
+// Cookie adds a cookie to this request only, see Params.Cookies.
+func (b *RequestBuilder) Cookie(c *http.Cookie) *RequestBuilder {
+	b.rq.cookies = append(b.rq.cookies, c)
+	return b
+}
+
+// Host sends h as the Host header instead of the host of the URL, e.g. to
+// reach a virtual host through a specific backend address.
+func (b *RequestBuilder) Host(h string) *RequestBuilder {
+	b.rq.host = h
+	return b
+}
+
+// Body sets the request body. Retries resend it if it is an in-memory reader
+// or an io.Seeker.
+func (b *RequestBuilder) Body(r io.Reader) *RequestBuilder {
+	b.rq.body = r
+	return b
+}
+
+// Timeout overrides Options.Timeout for each attempt, like Params.Timeout.
+func (b *RequestBuilder) Timeout(d time.Duration) *RequestBuilder {
+	b.rq.timeout = d
+	return b
+}
+
+// Context sets the context the request is sent with.
+func (b *RequestBuilder) Context(ctx context.Context) *RequestBuilder {
+	b.rq.ctx = ctx
+	return b
+}
+
+// Send sends the request and reads the response, like WebClient.Send.
+func (b *RequestBuilder) Send() (Response, error) {
+	return b.w.fetch(b.rq)
+}
@@ -0,0 +1,137 @@
+package brauser
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// cache keeps validated GET responses for conditional requests, evicting the
+// least recently used entry once max entries are stored.
+type cache struct {
+	max int
+
+	mu      sync.Mutex
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	url          string
+	etag         string
+	lastModified string
+	resp         Response
+}
+
+func newCache(o Options) *cache {
+	if o.CacheEntries <= 0 {
+		return nil
+	}
+	return &cache{max: o.CacheEntries, lru: list.New(), entries: map[string]*list.Element{}}
+}
+
+// ClearCache drops all cached responses.
+func (w *WebClient) ClearCache() {
+	c := w.cache
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.lru.Init()
+	c.entries = map[string]*list.Element{}
+	c.mu.Unlock()
+}
+
+// prepare turns req into a conditional request if a response for its URL is
+// cached, and returns that entry. Requests that already carry validators are
+// left alone.
+func (c *cache) prepare(req *http.Request) *cacheEntry {
+	if c == nil || req.Method != "GET" {
+		return nil
+	}
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	el, ok := c.entries[req.URL.String()]
+	if ok {
+		c.lru.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	e := el.Value.(*cacheEntry)
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+	return e
+}
+
+// complete handles the outcome of a GET request prepared with prepare: a 304
+// answer is replaced by the cached response, and new responses with
+// validators are stored unless the server forbids it with no-store.
+func (c *cache) complete(req *http.Request, cached *cacheEntry, r Response, err error) (Response, error) {
+	if c == nil || req.Method != "GET" {
+		return r, err
+	}
+
+	if cached != nil && r.StatusCode == http.StatusNotModified {
+		var se *HTTPStatusError
+		if err != nil && !(errors.As(err, &se) && se.Code == http.StatusNotModified) {
+			return r, err
+		}
+		// Headers sent with the 304 update the stored ones.
+		h := cached.resp.Header.Clone()
+		for k, v := range r.Header {
+			if k != "Content-Length" {
+				h[k] = v
+			}
+		}
+		return Response{
+			StatusCode: cached.resp.StatusCode,
+			Header:     h,
+			Body:       append([]byte(nil), cached.resp.Body...),
+			URL:        r.URL,
+			Attempts:   r.Attempts,
+		}, nil
+	}
+
+	if err != nil || r.StatusCode != http.StatusOK {
+		return r, err
+	}
+	e := &cacheEntry{
+		url:          req.URL.String(),
+		etag:         r.Header.Get("ETag"),
+		lastModified: r.Header.Get("Last-Modified"),
+	}
+	if e.etag == "" && e.lastModified == "" || strings.Contains(r.Header.Get("Cache-Control"), "no-store") {
+		return r, err
+	}
+	e.resp = Response{StatusCode: r.StatusCode, Header: r.Header.Clone(), Body: append([]byte(nil), r.Body...)}
+	c.store(e)
+	return r, err
+}
+
+func (c *cache) store(e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.url]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[e.url] = c.lru.PushFront(e)
+	for c.lru.Len() > c.max {
+		old := c.lru.Remove(c.lru.Back()).(*cacheEntry)
+		delete(c.entries, old.url)
+	}
+}
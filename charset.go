@@ -0,0 +1,42 @@
+package brauser
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// decodeCharset transcodes a textual body to UTF-8 when its charset is
+// declared by a byte order mark or the Content-Type header. HTML that isn't
+// valid UTF-8 is decoded as declared by its meta tags, or as windows-1252
+// like browsers do. Other bodies are left alone, as is the body if decoding
+// fails.
+func (w *WebClient) decodeCharset(r *Response) {
+	ct := r.Header.Get("Content-Type")
+	mt, params, err := mime.ParseMediaType(ct)
+	if ct != "" && (err != nil || !textual(mt)) {
+		return
+	}
+
+	enc, name, certain := charset.DetermineEncoding(r.Body, ct)
+	if !certain && (!strings.Contains(mt, "html") || utf8.Valid(r.Body)) || name == "utf-8" {
+		return
+	}
+	body, err := enc.NewDecoder().Bytes(r.Body)
+	if err != nil {
+		w.logf("decoding %s body: %v", name, err)
+		return
+	}
+	r.Body = body
+	if ct != "" {
+		params["charset"] = "utf-8"
+		r.Header.Set("Content-Type", mime.FormatMediaType(mt, params))
+	}
+}
+
+// textual reports whether media type mt holds text that may carry a charset.
+func textual(mt string) bool {
+	return strings.HasPrefix(mt, "text/") || strings.Contains(mt, "xml") || strings.Contains(mt, "json") || mt == "application/javascript"
+}
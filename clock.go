@@ -0,0 +1,31 @@
+package brauser
+
+import (
+	"context"
+	"time"
+)
+
+// clock is the time source for retry backoff, rate limiting and the circuit
+// breaker, so tests can replace it with one they advance by hand.
+type clock interface {
+	Now() time.Time
+	// Sleep waits for d or until ctx is done, whichever comes first.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the clock used outside of tests.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
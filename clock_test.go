@@ -0,0 +1,185 @@
+package brauser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock only advances when slept on or moved forward by advance, and
+// records the sleeps.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func (c *fakeClock) sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.slept...)
+}
+
+// maxJitter always picks the longest delay, minJitter the shortest.
+func maxJitter(n int64) int64 { return n - 1 }
+func minJitter(n int64) int64 { return 0 }
+
+func TestBackoffSchedule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		jitter func(int64) int64
+		want   []time.Duration
+	}{
+		{"max jitter", maxJitter, []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, time.Second}},
+		{"min jitter", minJitter, []time.Duration{50 * ms, 100 * ms, 200 * ms, 400 * ms, 500 * ms}},
+	}
+	for _, tt := range tests {
+		o := DefaultOptions()
+		o.Tries = 6
+		o.RetryBaseDelay = 100 * ms
+		o.RetryMaxDelay = time.Second
+		clk := newFakeClock()
+		w := newWebClientClock(o, clk, tt.jitter)
+
+		start := time.Now()
+		resp, err := w.GetResponse(srv.URL, nil)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("%s: got %d, %v", tt.name, resp.StatusCode, err)
+		}
+		if got := clk.sleeps(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: slept %v, want %v", tt.name, got, tt.want)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("%s: took %v of real time", tt.name, d)
+		}
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	clk := newFakeClock()
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Retry-After", clk.Now().Add(7*time.Second).Format(http.TimeFormat))
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.Tries = 2
+	w := newWebClientClock(o, clk, maxJitter)
+	w.Get(srv.URL, nil)
+	if got := clk.sleeps(); !reflect.DeepEqual(got, []time.Duration{7 * time.Second}) {
+		t.Errorf("slept %v, want the 7s asked for by Retry-After", got)
+	}
+}
+
+func TestBreakerCooldown(t *testing.T) {
+	var failing int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.Tries = 1
+	o.BreakerThreshold = 2
+	o.BreakerCooldown = time.Minute
+	clk := newFakeClock()
+	w := newWebClientClock(o, clk, maxJitter)
+
+	for i := 0; i < 2; i++ {
+		if _, err := w.Get(srv.URL, nil); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if w.Healthy() {
+		t.Fatal("breaker still closed after 2 failures")
+	}
+	if _, err := w.Get(srv.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v while open, want ErrCircuitOpen", err)
+	}
+
+	clk.advance(59 * time.Second)
+	if _, err := w.Get(srv.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v before the cooldown ended, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe reopens the circuit for another cooldown.
+	clk.advance(time.Second)
+	if _, err := w.Get(srv.URL, nil); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if _, err := w.Get(srv.URL, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v after a failed probe, want ErrCircuitOpen", err)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	clk.advance(time.Minute)
+	if _, err := w.Get(srv.URL, nil); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if !w.Healthy() {
+		t.Error("breaker still open after a successful probe")
+	}
+}
+
+func TestRateLimitWaits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.RateLimit = 2
+	o.RateBurst = 1
+	clk := newFakeClock()
+	w := newWebClientClock(o, clk, maxJitter)
+
+	for i := 0; i < 3; i++ {
+		if _, err := w.Get(srv.URL, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	if got := clk.sleeps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("slept %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,368 @@
+package brauser
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// ExportCookies writes the cookies the client would send to site to file as
+// JSON. The file is gzip compressed if its name ends in ".gz"; the import
+// methods recognise compressed files regardless of their name.
+func (w *WebClient) ExportCookies(file, site string) error {
+	if w.cl.Jar == nil {
+		return ErrCookiesDisabled
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(w.cl.Jar.Cookies(u))
+	if err != nil {
+		return err
+	}
+	err = writeCookieFile(file, data)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ImportCookies loads cookies written by ExportCookies into the jar for site.
+// Imported cookies are merged with the existing ones, replacing cookies of the
+// same name. Use ReplaceCookies to start from an empty jar instead.
+func (w *WebClient) ImportCookies(file, site string) error {
+	if w.cl.Jar == nil {
+		return ErrCookiesDisabled
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return err
+	}
+	d, err := readCookieFile(file)
+	if err != nil {
+		return err
+	}
+
+	var cookies []*http.Cookie
+
+	err = json.Unmarshal(d, &cookies)
+	if err != nil {
+		return err
+	}
+
+	w.cl.Jar.SetCookies(u, w.matchingCookies(u, cookies))
+
+	return nil
+}
+
+// ReplaceCookies clears the jar and then imports file like ImportCookies, so
+// only the imported cookies remain.
+func (w *WebClient) ReplaceCookies(file, site string) error {
+	if _, err := w.ownJar(); err != nil {
+		return err
+	}
+	w.ClearCookies()
+	return w.ImportCookies(file, site)
+}
+
+// matchingCookies filters out cookies whose Domain attribute does not cover
+// u's host. The jar would drop them silently, so they are logged instead.
+func (w *WebClient) matchingCookies(u *url.URL, cookies []*http.Cookie) []*http.Cookie {
+	host := strings.ToLower(u.Hostname())
+	kept := cookies[:0]
+	for _, c := range cookies {
+		d := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if d != "" && host != d && !strings.HasSuffix(host, "."+d) {
+			w.logf("discarding cookie %q: domain %q does not match %s", c.Name, c.Domain, host)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// ExportAllCookies writes every cookie in the jar, for all sites, to file.
+// Unlike ExportCookies it keeps domain, path and expiry, so ImportAllCookies
+// restores each cookie for the site that set it. Like ExportCookies it
+// compresses files named "*.gz".
+func (w *WebClient) ExportAllCookies(file string) error {
+	jar, err := w.ownJar()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(jar.all())
+	if err != nil {
+		return err
+	}
+	return writeCookieFile(file, data)
+}
+
+// ImportAllCookies loads a file written by ExportAllCookies, merging its
+// cookies into the jar.
+func (w *WebClient) ImportAllCookies(file string) error {
+	jar, err := w.ownJar()
+	if err != nil {
+		return err
+	}
+	d, err := readCookieFile(file)
+	if err != nil {
+		return err
+	}
+	var entries []cookieEntry
+	if err = json.Unmarshal(d, &entries); err != nil {
+		return err
+	}
+	return jar.restore(entries)
+}
+
+// Cookies returns the cookies the client would send to site.
+func (w *WebClient) Cookies(site string) ([]*http.Cookie, error) {
+	if w.cl.Jar == nil {
+		return nil, ErrCookiesDisabled
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return nil, err
+	}
+	return w.cl.Jar.Cookies(u), nil
+}
+
+// SetCookie stores c in the jar as if it had been set by site.
+func (w *WebClient) SetCookie(site string, c *http.Cookie) error {
+	if w.cl.Jar == nil {
+		return ErrCookiesDisabled
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return err
+	}
+	w.cl.Jar.SetCookies(u, []*http.Cookie{c})
+	return nil
+}
+
+// DeleteCookie removes the cookies named name that the client would send to
+// site. cookiejar.Jar can't delete cookies, so each one is overwritten with an
+// expired copy carrying the same domain and path, which the jar drops.
+func (w *WebClient) DeleteCookie(site, name string) error {
+	jar, err := w.ownJar()
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return err
+	}
+	jar.remove(u, name)
+	return nil
+}
+
+// ClearCookies drops all cookies by replacing the jar with an empty one. It
+// does nothing for a jar supplied through WrapClient.
+func (w *WebClient) ClearCookies() {
+	if w.jar == nil {
+		return
+	}
+	w.jar.reset()
+}
+
+// ownJar returns the jar created by brauser, which some operations need
+// because http.CookieJar can't list or remove cookies.
+func (w *WebClient) ownJar() (*cookieJar, error) {
+	switch {
+	case w.cl.Jar == nil:
+		return nil, ErrCookiesDisabled
+	case w.jar == nil:
+		return nil, ErrForeignJar
+	}
+	return w.jar, nil
+}
+
+// cookieJar wraps a cookiejar.Jar so it can be reset while requests that
+// use it are in flight. It also keeps a record of every cookie it was given,
+// because cookiejar.Jar offers no way to list its contents.
+type cookieJar struct {
+	mu      sync.RWMutex
+	jar     *cookiejar.Jar
+	psl     cookiejar.PublicSuffixList
+	entries map[string]cookieEntry
+
+	// onChange, if set, is called after the jar was modified.
+	onChange func()
+}
+
+// cookieEntry is a cookie together with the URL that set it.
+type cookieEntry struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+func newCookieJar(psl cookiejar.PublicSuffixList) *cookieJar {
+	j := &cookieJar{psl: psl}
+	j.reset()
+	return j
+}
+
+// publicSuffixList returns the list the cookie jar is created with.
+func publicSuffixList(o Options) cookiejar.PublicSuffixList {
+	switch {
+	case o.DisablePublicSuffixList:
+		return nil
+	case o.PublicSuffixList != nil:
+		return o.PublicSuffixList
+	}
+	return publicsuffix.List
+}
+
+func (j *cookieJar) reset() {
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: j.psl})
+	j.mu.Lock()
+	j.jar = jar
+	j.entries = map[string]cookieEntry{}
+	j.mu.Unlock()
+	j.changed()
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	j.jar.SetCookies(u, cookies)
+	for _, c := range cookies {
+		j.record(u, c)
+	}
+	j.mu.Unlock()
+	j.changed()
+}
+
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.jar.Cookies(u)
+}
+
+// record mirrors the jar's bookkeeping for c: cookies are identified by
+// domain, path and name, and expired ones are removed.
+func (j *cookieJar) record(u *url.URL, c *http.Cookie) {
+	host := strings.ToLower(u.Hostname())
+	domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+	if domain == "" {
+		domain = host
+	} else if host != domain && !strings.HasSuffix(host, "."+domain) {
+		// Rejected by the jar as well.
+		return
+	} else if host != domain && j.psl != nil && j.psl.PublicSuffix(domain) == domain {
+		return
+	}
+	p := c.Path
+	if !strings.HasPrefix(p, "/") {
+		p = defaultCookiePath(u.Path)
+	}
+	key := domain + ";" + p + ";" + c.Name
+
+	now := time.Now()
+	if c.MaxAge < 0 || (!c.Expires.IsZero() && !c.Expires.After(now)) {
+		delete(j.entries, key)
+		return
+	}
+
+	cc := *c
+	if cc.MaxAge > 0 {
+		cc.Expires = now.Add(time.Duration(cc.MaxAge) * time.Second)
+		cc.MaxAge = 0
+	}
+	cc.Path = p
+	j.entries[key] = cookieEntry{URL: (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: p}).String(), Cookie: &cc}
+}
+
+// all returns the unexpired cookies in the jar, ordered by domain, path and
+// name.
+func (j *cookieJar) all() []cookieEntry {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	keys := make([]string, 0, len(j.entries))
+	for k := range j.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	entries := make([]cookieEntry, 0, len(keys))
+	for _, k := range keys {
+		e := j.entries[k]
+		if !e.Cookie.Expires.IsZero() && !e.Cookie.Expires.After(now) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// remove expires the cookies named name that apply to u.
+func (j *cookieJar) remove(u *url.URL, name string) {
+	host := strings.ToLower(u.Hostname())
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+
+	var expired []cookieEntry
+	for _, e := range j.all() {
+		c := e.Cookie
+		if c.Name != name {
+			continue
+		}
+		eu, err := url.Parse(e.URL)
+		if err != nil {
+			continue
+		}
+		d := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if d == "" {
+			d = strings.ToLower(eu.Hostname())
+		}
+		if host != d && (c.Domain == "" || !strings.HasSuffix(host, "."+d)) {
+			continue
+		}
+		if !strings.HasPrefix(p, c.Path) || len(p) > len(c.Path) && !strings.HasSuffix(c.Path, "/") && p[len(c.Path)] != '/' {
+			continue
+		}
+		expired = append(expired, cookieEntry{URL: e.URL, Cookie: &http.Cookie{Name: name, Domain: c.Domain, Path: c.Path, MaxAge: -1}})
+	}
+	j.restore(expired)
+}
+
+// restore adds entries previously returned by all.
+func (j *cookieJar) restore(entries []cookieEntry) error {
+	for _, e := range entries {
+		u, err := url.Parse(e.URL)
+		if err != nil {
+			return err
+		}
+		if e.Cookie != nil {
+			j.SetCookies(u, []*http.Cookie{e.Cookie})
+		}
+	}
+	return nil
+}
+
+func (j *cookieJar) changed() {
+	if j.onChange != nil {
+		j.onChange()
+	}
+}
+
+// defaultCookiePath is the path a cookie without Path attribute applies to,
+// as defined in RFC 6265 section 5.1.4.
+func defaultCookiePath(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return p[:i]
+}
@@ -0,0 +1,145 @@
+package brauser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cookieNames returns the sorted names of cookies.
+func cookieNames(cookies []*http.Cookie) string {
+	names := make([]string, len(cookies))
+	for i, c := range cookies {
+		names[i] = c.Name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func TestDeleteCookieNotSent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/login" {
+			http.SetCookie(rw, &http.Cookie{Name: "session", Value: "s", Path: "/"})
+			http.SetCookie(rw, &http.Cookie{Name: "theme", Value: "dark", Path: "/"})
+			// Without Path, this one applies to /app only.
+			http.SetCookie(rw, &http.Cookie{Name: "app", Value: "a"})
+			return
+		}
+		rw.Write([]byte(cookieNames(r.Cookies())))
+	}))
+	defer srv.Close()
+
+	w := CreateWebClient()
+	if _, err := w.Get(srv.URL+"/app/login", nil); err != nil {
+		t.Fatal(err)
+	}
+	check := func(path, want string) {
+		t.Helper()
+		body, err := w.Get(srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != want {
+			t.Errorf("%s: server got cookies %q, want %q", path, body, want)
+		}
+	}
+	check("/app/page", "app,session,theme")
+
+	if err := w.DeleteCookie(srv.URL+"/app/page", "session"); err != nil {
+		t.Fatal(err)
+	}
+	check("/app/page", "app,theme")
+	check("/", "theme")
+
+	// Deleting for a site the cookie doesn't apply to keeps it.
+	if err := w.DeleteCookie(srv.URL+"/", "app"); err != nil {
+		t.Fatal(err)
+	}
+	check("/app/page", "app,theme")
+	if err := w.DeleteCookie(srv.URL+"/app", "app"); err != nil {
+		t.Fatal(err)
+	}
+	check("/app/page", "theme")
+}
+
+func TestDeleteCookieDomain(t *testing.T) {
+	w := CreateWebClient()
+	w.SetCookie("http://www.example.com/", &http.Cookie{Name: "id", Value: "1", Domain: "example.com", Path: "/"})
+	w.SetCookie("http://www.example.com/", &http.Cookie{Name: "id", Value: "2", Path: "/"})
+	w.SetCookie("http://other.example.com/", &http.Cookie{Name: "id", Value: "3", Path: "/"})
+
+	if err := w.DeleteCookie("http://www.example.com/", "id"); err != nil {
+		t.Fatal(err)
+	}
+	for site, want := range map[string]string{
+		"http://www.example.com/":   "",
+		"http://example.com/":       "",
+		"http://other.example.com/": "id",
+	} {
+		cookies, _ := w.Cookies(site)
+		if got := cookieNames(cookies); got != want {
+			t.Errorf("%s: cookies %q, want %q", site, got, want)
+		}
+	}
+	if n := len(w.jar.all()); n != 1 {
+		t.Errorf("jar lists %d cookies, want only the one for other.example.com", n)
+	}
+}
+
+func TestDeleteCookieForeignJar(t *testing.T) {
+	w := WrapClient(&http.Client{Jar: foreignJar{}}, Options{})
+	if err := w.DeleteCookie("http://example.com/", "id"); err != ErrForeignJar {
+		t.Errorf("err = %v, want ErrForeignJar", err)
+	}
+	w = CreateWebClient(Options{DisableCookies: true})
+	if err := w.DeleteCookie("http://example.com/", "id"); err != ErrCookiesDisabled {
+		t.Errorf("err = %v, want ErrCookiesDisabled", err)
+	}
+}
+
+type foreignJar struct{}
+
+func (foreignJar) SetCookies(*url.URL, []*http.Cookie) {}
+func (foreignJar) Cookies(*url.URL) []*http.Cookie     { return nil }
+
+func TestRetrySendsCookiesOnce(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, strings.Join(r.Header["Cookie"], " | "))
+		n := len(got)
+		mu.Unlock()
+		switch n {
+		case 1:
+			http.SetCookie(rw, &http.Cookie{Name: "s", Value: "2", Path: "/"})
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			rw.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	o := fastRetries(2)
+	o.OnUnauthorized = func() error { return nil }
+	w := CreateWebClient(o)
+	w.SetCookie(srv.URL, &http.Cookie{Name: "s", Value: "1", Path: "/"})
+
+	p := Params{Cookies: []*http.Cookie{{Name: "p", Value: "x"}}}
+	if _, err := w.Send(context.Background(), "GET", srv.URL, p, nil); err != nil {
+		t.Fatal(err)
+	}
+	// The 503 is retried, the 401 re-sent after refreshing credentials.
+	want := []string{"p=x; s=1", "p=x; s=2", "p=x; s=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cookie headers %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package brauser
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// newBrotliReader is set when brotli support is compiled in.
+var newBrotliReader func(io.Reader) io.ReadCloser
+
+// responseBody returns the body of resp to read, decompressed unless
+// Options.DisableDecompression is set. The returned closer releases the
+// decoder, not the underlying body.
+func (w *WebClient) responseBody(resp *http.Response) (io.Reader, io.Closer, error) {
+	if w.options.DisableDecompression {
+		return resp.Body, nopCloser{}, nil
+	}
+	return w.decompressBody(resp)
+}
+
+// decompressBody wraps resp.Body in a decoder matching its Content-Encoding.
+// Go's transport only decompresses gzip by itself when it added the
+// Accept-Encoding header, so responses to requests with a caller supplied
+// Accept-Encoding arrive compressed. On success the encoding headers are
+// removed, as the transport does.
+func (w *WebClient) decompressBody(resp *http.Response) (io.Reader, io.Closer, error) {
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		rc, err = gzip.NewReader(resp.Body)
+	case "deflate":
+		rc, err = newDeflateReader(resp.Body)
+	case "br":
+		if newBrotliReader == nil || !w.acceptsBrotli(resp.Request) {
+			return resp.Body, nopCloser{}, nil
+		}
+		rc = newBrotliReader(resp.Body)
+	default:
+		return resp.Body, nopCloser{}, nil
+	}
+	if err == io.EOF {
+		// Nothing to decode, e.g. a HEAD or 204 response.
+		return resp.Body, nopCloser{}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return rc, rc, nil
+}
+
+// acceptsBrotli reports whether the caller advertised br in the
+// Accept-Encoding header of req, and brotli decoding isn't disabled.
+func (w *WebClient) acceptsBrotli(req *http.Request) bool {
+	if w.options.DisableBrotli || req == nil {
+		return false
+	}
+	for _, enc := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if i := strings.IndexByte(enc, ';'); i >= 0 {
+			enc = enc[:i]
+		}
+		if strings.EqualFold(strings.TrimSpace(enc), "br") {
+			return true
+		}
+	}
+	return false
+}
+
+// newDeflateReader handles both the zlib wrapped format mandated for
+// "deflate" and the raw deflate streams some servers send instead.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	h, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
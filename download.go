@@ -0,0 +1,106 @@
+package brauser
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Download streams the body of a GET request for path to dst and returns the
+// number of bytes written, without holding the body in memory. Connection
+// failures and retryable statuses are retried as usual. A failure while
+// copying the body is retried with Options.RetryBodyErrors if dst can be
+// rewound, i.e. it implements io.Seeker and Truncate like *os.File does; the
+// download then restarts from scratch. Restarts count against Tries.
+func (w *WebClient) Download(path string, params map[string]string, dst io.Writer) (int64, error) {
+	return w.DownloadWithProgress(path, params, dst, nil)
+}
+
+// ProgressFunc is called while a download is copied to its destination with
+// the number of bytes written so far and the size of the body, or -1 if the
+// server didn't announce it, as with chunked or compressed responses.
+type ProgressFunc func(written, total int64)
+
+// DownloadWithProgress is like Download but calls progress, if not nil, after
+// every chunk written to dst. When a download restarts, written starts over
+// from zero.
+func (w *WebClient) DownloadWithProgress(path string, params map[string]string, dst io.Writer, progress ProgressFunc) (n int64, err error) {
+	req, err := w.newRequest(request{ctx: context.Background(), method: "GET", path: path, header: params})
+	if err != nil {
+		return
+	}
+	if w.options.DryRun {
+		w.logDryRun(req)
+		return 0, ErrDryRun
+	}
+
+	rw, canRewind := dst.(rewindable)
+	var start int64
+	if canRewind {
+		if start, err = rw.Seek(0, io.SeekCurrent); err != nil {
+			canRewind = false
+		}
+	}
+
+	var status []byte // the body of an error status with ErrorOnStatus
+	resp, x, err := w.send(req, 0, func(resp *http.Response) (bool, error) {
+		if n > 0 {
+			// An earlier attempt wrote part of the body.
+			if _, err := rw.Seek(start, io.SeekStart); err != nil {
+				return false, err
+			}
+			if err := rw.Truncate(start); err != nil {
+				return false, err
+			}
+			n = 0
+		}
+		err := w.readBody(resp, func(body io.Reader) (err error) {
+			if w.options.ErrorOnStatus && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+				status, err = ioutil.ReadAll(io.LimitReader(body, 64<<10))
+				return err
+			}
+			out := dst
+			if progress != nil {
+				out = &progressWriter{w: dst, total: resp.ContentLength, progress: progress}
+			}
+			n, err = io.Copy(out, body)
+			return err
+		})
+		// Without rewinding dst, only a failure before the first write can
+		// be retried.
+		return canRewind || n == 0, err
+	})
+	if err == nil {
+		resp.Body.Close()
+		if w.options.ErrorOnStatus && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+			err = &HTTPStatusError{Code: resp.StatusCode, Body: status}
+		}
+	}
+	code := 0
+	if resp != nil {
+		code = resp.StatusCode
+	}
+	w.report(req, x, code, err)
+	return n, err
+}
+
+type rewindable interface {
+	io.Seeker
+	Truncate(size int64) error
+}
+
+// progressWriter reports the bytes written through it to a ProgressFunc.
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.progress(p.written, p.total)
+	return n, err
+}
@@ -0,0 +1,138 @@
+package brauser
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadRestarts(t *testing.T) {
+	var n, failures int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) <= atomic.LoadInt32(&failures) {
+			truncate(rw)
+			return
+		}
+		rw.Write([]byte("complete"))
+	}))
+	defer srv.Close()
+
+	f, err := ioutil.TempFile("", "brauser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	tests := []struct {
+		name           string
+		failures       int32
+		retry          bool
+		buffer         bool // download into a bytes.Buffer instead of a file
+		wantRequests   int32
+		wantBodyError  bool
+		wantRetryError bool
+	}{
+		{name: "restart into file", failures: 1, retry: true, wantRequests: 2},
+		{name: "attempts exhausted", failures: 5, retry: true, wantRequests: 3, wantBodyError: true, wantRetryError: true},
+		{name: "no RetryBodyErrors", failures: 1, wantRequests: 1, wantBodyError: true},
+		{name: "buffer can't rewind", failures: 1, retry: true, buffer: true, wantRequests: 1, wantBodyError: true},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&n, 0)
+		atomic.StoreInt32(&failures, tt.failures)
+		f.Truncate(0)
+		f.Seek(0, 0)
+
+		o := fastRetries(3)
+		o.RetryBodyErrors = tt.retry
+		metrics := recordMetrics(&o)
+		w := CreateWebClient(o)
+
+		var written int64
+		if tt.buffer {
+			written, err = w.Download(srv.URL, nil, &bytes.Buffer{})
+		} else {
+			written, err = w.Download(srv.URL, nil, f)
+		}
+
+		if got := atomic.LoadInt32(&n); got != tt.wantRequests {
+			t.Errorf("%s: %d requests, want %d", tt.name, got, tt.wantRequests)
+		}
+		if m := metrics(); len(m) != 1 || m[0].Attempts != int(tt.wantRequests) {
+			t.Errorf("%s: OnComplete got %+v, want one call for %d attempts", tt.name, m, tt.wantRequests)
+		}
+		var be *BodyError
+		if errors.As(err, &be) != tt.wantBodyError {
+			t.Errorf("%s: err = %v, want a *BodyError: %v", tt.name, err, tt.wantBodyError)
+		}
+		var re *RetryError
+		if errors.As(err, &re) != tt.wantRetryError {
+			t.Errorf("%s: err = %v, want a *RetryError: %v", tt.name, err, tt.wantRetryError)
+		}
+		if err != nil || tt.buffer {
+			continue
+		}
+		data, _ := ioutil.ReadFile(f.Name())
+		if string(data) != "complete" || written != int64(len(data)) {
+			t.Errorf("%s: file holds %q, %d bytes reported", tt.name, data, written)
+		}
+	}
+}
+
+func TestDownloadWithinTotalTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		truncate(rw)
+	}))
+	defer srv.Close()
+
+	f, err := ioutil.TempFile("", "brauser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	o := fastRetries(100)
+	o.RetryBaseDelay = 40 * time.Millisecond
+	o.RetryMaxDelay = 40 * time.Millisecond
+	o.TotalTimeout = 100 * time.Millisecond
+	o.RetryBodyErrors = true
+	w := CreateWebClient(o)
+
+	start := time.Now()
+	_, err = w.Download(srv.URL, nil, f)
+	if !errors.Is(err, ErrTotalTimeout) {
+		t.Errorf("err = %v, want ErrTotalTimeout", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("download took %v, TotalTimeout didn't bound the restarts", d)
+	}
+}
+
+func TestDownloadErrorOnStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("no such file"))
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.ErrorOnStatus = true
+	w := CreateWebClient(o)
+	var buf bytes.Buffer
+	_, err := w.Download(srv.URL, nil, &buf)
+	var se *HTTPStatusError
+	if !errors.As(err, &se) || se.Code != http.StatusNotFound || string(se.Body) != "no such file" {
+		t.Errorf("err = %v, want an *HTTPStatusError with the body", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("error body written to dst: %q", buf.Bytes())
+	}
+}
@@ -0,0 +1,166 @@
+package brauser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit
+// breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("brauser: circuit open after repeated failures")
+
+// ErrTimeout and ErrConnectionRefused classify transport errors. They are
+// matched with errors.Is; the original error stays available to errors.As.
+var (
+	ErrTimeout           = errors.New("brauser: request timed out")
+	ErrConnectionRefused = errors.New("brauser: connection refused")
+)
+
+// ErrTotalTimeout is matched by errors from requests that ran out of
+// Options.TotalTimeout.
+var ErrTotalTimeout = errors.New("brauser: total timeout exceeded")
+
+// ErrBodyNotReplayable is reported, wrapped around the last attempt's error,
+// when a request should be retried but its body was consumed and can't be
+// rewound. Pass an io.Seeker, a bytes.Reader or similar to allow retries.
+var ErrBodyNotReplayable = errors.New("brauser: request body can't be replayed")
+
+// ErrInvalidURL and ErrInvalidMethod are returned before anything is sent
+// when a request can't possibly succeed.
+var (
+	ErrInvalidURL    = errors.New("brauser: invalid URL")
+	ErrInvalidMethod = errors.New("brauser: invalid HTTP method")
+)
+
+// ErrCookiesDisabled is returned by the cookie methods of a client created
+// with Options.DisableCookies.
+var ErrCookiesDisabled = errors.New("brauser: cookies are disabled for this client")
+
+// ErrDNSResolution matches errors for requests to host names that don't
+// exist. They are not retried unless Options.RetryIf says so.
+var ErrDNSResolution = errors.New("brauser: host not found")
+
+// ErrForeignJar is returned by cookie methods that need to list or clear the
+// jar when the client uses a jar not created by brauser, see WrapClient.
+var ErrForeignJar = errors.New("brauser: operation not supported by the client's cookie jar")
+
+// ErrDryRun is returned by methods that hand out the raw response, like Do
+// and Download, when Options.DryRun is set.
+var ErrDryRun = errors.New("brauser: not sent in dry run mode")
+
+// ErrResponseTooLarge is returned when a response body exceeds
+// Options.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("brauser: response body too large")
+
+// HTTPStatusError is returned when Options.ErrorOnStatus is set and the
+// server answers with a status code outside the 2xx range. The body is kept
+// so callers can still inspect the error payload.
+type HTTPStatusError struct {
+	Code int
+	Body []byte
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("brauser: unexpected status %d %s", e.Code, http.StatusText(e.Code))
+}
+
+// BodyError is returned when reading a response body fails partway. The
+// response returned with it holds the bytes read before the failure. Read and
+// Expected count bytes as sent by the server, before decompression; Expected
+// is -1 if the response did not declare a Content-Length.
+type BodyError struct {
+	Read     int64
+	Expected int64
+	Err      error
+}
+
+func (e *BodyError) Error() string {
+	if e.Expected >= 0 {
+		return fmt.Sprintf("brauser: reading response body: got %d of %d bytes: %v", e.Read, e.Expected, e.Err)
+	}
+	return fmt.Sprintf("brauser: reading response body: failed after %d bytes: %v", e.Read, e.Err)
+}
+
+func (e *BodyError) Unwrap() error { return e.Err }
+
+// kindError tags a transport error with one of the sentinel errors above.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string        { return e.err.Error() }
+func (e *kindError) Unwrap() error        { return e.err }
+func (e *kindError) Is(target error) bool { return target == e.kind }
+
+// classify wraps err so that errors.Is recognises timeouts and refused
+// connections.
+func classify(err error) error {
+	var ne net.Error
+	switch {
+	case err == nil:
+		return nil
+	case notFound(err):
+		return &kindError{kind: ErrDNSResolution, err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &ne) && ne.Timeout():
+		return &kindError{kind: ErrTimeout, err: err}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return &kindError{kind: ErrConnectionRefused, err: err}
+	}
+	return err
+}
+
+// notFound reports whether err says that a host name does not exist.
+func notFound(err error) bool {
+	var de *net.DNSError
+	return errors.As(err, &de) && de.IsNotFound
+}
+
+// Attempt records the outcome of a single attempt of a request: either the
+// status code of the response or the error.
+type Attempt struct {
+	StatusCode int
+	Err        error
+}
+
+func newAttempt(resp *http.Response, err error) Attempt {
+	if err != nil {
+		return Attempt{Err: err}
+	}
+	return Attempt{StatusCode: resp.StatusCode}
+}
+
+func (a Attempt) String() string {
+	if a.Err == nil {
+		return fmt.Sprint("status ", a.StatusCode)
+	}
+	var ue *url.Error
+	if errors.As(a.Err, &ue) {
+		return ue.Err.Error()
+	}
+	return a.Err.Error()
+}
+
+// RetryError is returned when a request failed after more than one attempt.
+// It lists what happened on every attempt; Err is the final error, which
+// errors.Is and errors.As look through to.
+type RetryError struct {
+	Attempts []Attempt
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	steps := make([]string, len(e.Attempts))
+	for i, a := range e.Attempts {
+		steps[i] = a.String()
+	}
+	return fmt.Sprintf("brauser: failed after %d attempts: %s", len(e.Attempts), strings.Join(steps, ", then "))
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
@@ -0,0 +1,13 @@
+package brauser
+
+import "io"
+
+// Fetcher is the basic request API of WebClient. Code depending on Fetcher
+// instead of *WebClient can be tested with a fake such as mock.Fetcher.
+type Fetcher interface {
+	Get(path string, params map[string]string) ([]byte, error)
+	Post(path string, params map[string]string, payload io.Reader) ([]byte, error)
+	CustomRequest(method, path string, params map[string]string, payload io.Reader) ([]byte, error)
+}
+
+var _ Fetcher = (*WebClient)(nil)
@@ -0,0 +1,109 @@
+package brauser
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PostForm posts values as an application/x-www-form-urlencoded body.
+func (w *WebClient) PostForm(path string, values url.Values) (Response, error) {
+	return w.fetch(request{
+		ctx:    context.Background(),
+		method: "POST",
+		path:   path,
+		header: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		body:   strings.NewReader(values.Encode()),
+	})
+}
+
+// PostMultipart posts fields and files as a multipart/form-data body. Each
+// entry of files is sent as a file part under its map key as the form field
+// name. Files are streamed rather than buffered; readers with a Name method
+// (such as *os.File) use its base name as the file name, other readers use
+// the field name. File parts are sent as application/octet-stream unless
+// Options.SniffContentType is set.
+func (w *WebClient) PostMultipart(path string, fields map[string]string, files map[string]io.Reader) (Response, error) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, fields, files, w.options.SniffContentType))
+	}()
+
+	return w.fetch(request{
+		ctx:    context.Background(),
+		method: "POST",
+		path:   path,
+		header: map[string]string{"Content-Type": mw.FormDataContentType()},
+		body:   pr,
+	})
+}
+
+func writeMultipart(mw *multipart.Writer, fields map[string]string, files map[string]io.Reader, sniff bool) error {
+	for _, k := range sortedKeys(fields) {
+		if err := mw.WriteField(k, fields[k]); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for k := range files {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		r := files[k]
+		name := fileName(k, r)
+		var (
+			part io.Writer
+			err  error
+		)
+		if sniff {
+			br := bufio.NewReaderSize(r, sniffLen)
+			head, _ := br.Peek(sniffLen)
+			h := make(textproto.MIMEHeader)
+			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(k), quoteEscaper.Replace(name)))
+			h.Set("Content-Type", detectContentType(name, head))
+			part, err = mw.CreatePart(h)
+			r = br
+		} else {
+			part, err = mw.CreateFormFile(k, name)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(part, r); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func fileName(field string, r io.Reader) string {
+	if n, ok := r.(interface{ Name() string }); ok && n.Name() != "" {
+		return filepath.Base(n.Name())
+	}
+	return field
+}
+
+// quoteEscaper escapes form field and file names like mime/multipart does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,3 +1,8 @@
 module github.com/grzfrmbl/brauser
 
 go 1.14
+
+require (
+	github.com/andybalholm/brotli v1.1.1
+	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
+)
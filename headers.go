@@ -0,0 +1,22 @@
+package brauser
+
+import "net/http"
+
+// SetDefaultHeader sets a header sent with every request, in place of the
+// one Options.UserAgent or Options.Accept would add. Headers passed with an
+// individual request take precedence.
+func (w *WebClient) SetDefaultHeader(key, value string) {
+	w.state.mu.Lock()
+	defer w.state.mu.Unlock()
+	if w.state.headers == nil {
+		w.state.headers = http.Header{}
+	}
+	w.state.headers.Set(key, value)
+}
+
+// RemoveDefaultHeader removes a header set with SetDefaultHeader.
+func (w *WebClient) RemoveDefaultHeader(key string) {
+	w.state.mu.Lock()
+	defer w.state.mu.Unlock()
+	w.state.headers.Del(key)
+}
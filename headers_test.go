@@ -0,0 +1,79 @@
+package brauser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// echoHeaders answers every request with the request headers, as set by the
+// client, in the response headers.
+func echoHeaders() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		for k, v := range r.Header {
+			rw.Header()["Echo-"+k] = v
+		}
+	}))
+}
+
+func TestDefaultHeaderPrecedence(t *testing.T) {
+	srv := echoHeaders()
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.Accept = "text/html"
+	w := CreateWebClient(o)
+
+	tests := []struct {
+		name          string
+		defaults      map[string]string
+		params        map[string]string
+		agent, accept string
+	}{
+		{"options", nil, nil, DefaultUserAgent, "text/html"},
+		{"default header", map[string]string{"User-Agent": "client/1", "Accept": "application/json"}, nil, "client/1", "application/json"},
+		{"request header", map[string]string{"User-Agent": "client/1"}, map[string]string{"User-Agent": "request/1"}, "request/1", "text/html"},
+	}
+	for _, tt := range tests {
+		for k, v := range tt.defaults {
+			w.SetDefaultHeader(k, v)
+		}
+		resp, err := w.GetResponse(srv.URL, tt.params)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := resp.Header.Get("Echo-User-Agent"); got != tt.agent {
+			t.Errorf("%s: User-Agent = %q, want %q", tt.name, got, tt.agent)
+		}
+		if got := resp.Header.Get("Echo-Accept"); got != tt.accept {
+			t.Errorf("%s: Accept = %q, want %q", tt.name, got, tt.accept)
+		}
+		for k := range tt.defaults {
+			w.RemoveDefaultHeader(k)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	srv := echoHeaders()
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.UserAgent = "crawler/2.0 (+https://example.com/bot)"
+	for _, tt := range []struct {
+		w    WebClient
+		want string
+	}{
+		{CreateWebClient(), DefaultUserAgent},
+		{CreateWebClient(Options{}), DefaultUserAgent},
+		{CreateWebClient(o), o.UserAgent},
+	} {
+		resp, err := tt.w.GetResponse(srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Header.Get("Echo-User-Agent"); got != tt.want {
+			t.Errorf("server got User-Agent %q, want %q", got, tt.want)
+		}
+	}
+}
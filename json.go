@@ -0,0 +1,49 @@
+package brauser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PostJSON marshals body to JSON and posts it with a matching Content-Type.
+func (w *WebClient) PostJSON(path string, body interface{}) (Response, error) {
+	return w.sendJSON("POST", path, body)
+}
+
+// PutJSON is like PostJSON but sends a PUT request.
+func (w *WebClient) PutJSON(path string, body interface{}) (Response, error) {
+	return w.sendJSON("PUT", path, body)
+}
+
+// PatchJSON is like PostJSON but sends a PATCH request.
+func (w *WebClient) PatchJSON(path string, body interface{}) (Response, error) {
+	return w.sendJSON("PATCH", path, body)
+}
+
+// GetJSON fetches path and decodes the response body into out.
+func (w *WebClient) GetJSON(path string, params map[string]string, out interface{}) error {
+	resp, err := w.GetResponse(path, params)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(resp.Body, out); err != nil {
+		return fmt.Errorf("brauser: decode response body: %w", err)
+	}
+	return nil
+}
+
+func (w *WebClient) sendJSON(method, path string, body interface{}) (Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Response{}, fmt.Errorf("brauser: encode request body: %w", err)
+	}
+	return w.fetch(request{
+		ctx:    context.Background(),
+		method: method,
+		path:   path,
+		header: map[string]string{"Content-Type": "application/json"},
+		body:   bytes.NewReader(data),
+	})
+}
@@ -0,0 +1,73 @@
+package brauser
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencies keeps the durations of the most recent successful requests in a
+// ring buffer.
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func newLatencies(o Options) *latencies {
+	if o.LatencySamples <= 0 {
+		return nil
+	}
+	return &latencies{samples: make([]time.Duration, o.LatencySamples)}
+}
+
+func (l *latencies) add(d time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.samples[l.next] = d
+	l.next++
+	if l.next == len(l.samples) {
+		l.next, l.full = 0, true
+	}
+	l.mu.Unlock()
+}
+
+// LatencyPercentile returns the p-th percentile, with p between 0 and 100,
+// of the time until the response headers arrived, retries included, over the
+// last Options.LatencySamples successful requests; e.g. 99 for the p99
+// latency.
+// It returns 0 if there are no samples or latency tracking is disabled.
+func (w *WebClient) LatencyPercentile(p float64) time.Duration {
+	l := w.latencies
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	n := l.next
+	if l.full {
+		n = len(l.samples)
+	}
+	s := append([]time.Duration(nil), l.samples[:n]...)
+	l.mu.Unlock()
+
+	if len(s) == 0 {
+		return 0
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	switch {
+	case p <= 0:
+		return s[0]
+	case p >= 100:
+		return s[len(s)-1]
+	}
+	// Nearest rank.
+	i := int(math.Ceil(p/100*float64(len(s)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return s[i]
+}
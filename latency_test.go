@@ -0,0 +1,29 @@
+package brauser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLatencyExcludesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("head"))
+		rw.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		rw.Write([]byte("tail"))
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.LatencySamples = 5
+	w := CreateWebClient(o)
+	body, err := w.Get(srv.URL, nil)
+	if err != nil || string(body) != "headtail" {
+		t.Fatalf("got %q, %v", body, err)
+	}
+	if p := w.LatencyPercentile(50); p == 0 || p >= 100*time.Millisecond {
+		t.Errorf("median latency %v, want the time until the headers arrived", p)
+	}
+}
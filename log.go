@@ -0,0 +1,194 @@
+package brauser
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultMaxLogBodyBytes = 1024
+
+// DefaultRedactHeaders are masked in logs when Options.RedactHeaders is nil.
+var DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Logger receives the client's log output. Wrap a *log.Logger l with
+// LoggerFunc(l.Printf).
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// LoggerFunc adapts an ordinary function to the Logger interface.
+type LoggerFunc func(format string, args ...interface{})
+
+func (f LoggerFunc) Logf(format string, args ...interface{}) {
+	f(format, args...)
+}
+
+// StructuredLogger is an optional extension of Logger. Loggers implementing
+// it receive events like retries as a message with key-value pairs, in the
+// style of logr or zap's SugaredLogger, instead of a formatted line.
+type StructuredLogger interface {
+	Logger
+	Logw(msg string, keysAndValues ...interface{})
+}
+
+type stdoutLogger struct{}
+
+func (stdoutLogger) Logf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Logf(string, ...interface{}) {}
+
+// DefaultLogger, if set, receives the log output of clients created
+// afterwards without their own Options.Logger or Verbose setting. It is meant
+// to be set once at startup, before any clients are created.
+var DefaultLogger Logger
+
+// newLogger picks the logger for the given options: a user supplied one,
+// stdout when verbose, DefaultLogger, or a no-op.
+func newLogger(o Options) Logger {
+	switch {
+	case o.Logger != nil:
+		return o.Logger
+	case o.Verbose:
+		return stdoutLogger{}
+	case DefaultLogger != nil:
+		return DefaultLogger
+	default:
+		return nopLogger{}
+	}
+}
+
+// logRetry logs that attempt number attempt of req failed, with err or the
+// response resp, and is retried after delay.
+func (w *WebClient) logRetry(req *http.Request, attempt int, delay time.Duration, resp *http.Response, err error) {
+	if !w.logsEnabled() {
+		return
+	}
+	if sl, ok := w.logger.(StructuredLogger); ok {
+		kv := []interface{}{"method", req.Method, "url", req.URL.String(), "attempt", attempt, "delay", delay}
+		if err != nil {
+			kv = append(kv, "reason", "error", "error", err)
+		} else {
+			kv = append(kv, "reason", "status", "status", resp.StatusCode)
+		}
+		sl.Logw("retry", kv...)
+		return
+	}
+	if err != nil {
+		w.logf("%s %s: retry %d after %v due to call failure, %v", req.Method, req.URL, attempt, delay, err)
+		return
+	}
+	w.logf("%s %s: retry %d after %v due to status %d", req.Method, req.URL, attempt, delay, resp.StatusCode)
+}
+
+// logsEnabled reports whether log output goes anywhere. Callers on hot paths
+// check it before calling logf, as merely passing the arguments allocates.
+func (w *WebClient) logsEnabled() bool {
+	switch w.logger.(type) {
+	case nil, nopLogger:
+		return false
+	}
+	return true
+}
+
+// logHeaders logs h with the values of sensitive headers masked.
+func (w *WebClient) logHeaders(prefix string, h http.Header) {
+	redact := w.options.RedactHeaders
+	if redact == nil {
+		redact = DefaultRedactHeaders
+	}
+
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := strings.Join(h[k], ", ")
+		for _, r := range redact {
+			if strings.EqualFold(k, r) {
+				v = "***"
+				break
+			}
+		}
+		w.logf("%s %s: %s", prefix, k, v)
+	}
+}
+
+// logBodies reports whether request and response bodies should be logged.
+func (w *WebClient) logBodies() bool {
+	return w.options.LogBodies && w.logsEnabled()
+}
+
+func (w *WebClient) maxLogBody() int {
+	if w.options.MaxLogBodyBytes > 0 {
+		return w.options.MaxLogBodyBytes
+	}
+	return defaultMaxLogBodyBytes
+}
+
+// logRequestBody logs a preview of the request body, read through GetBody so
+// the body that is sent stays intact.
+func (w *WebClient) logRequestBody(req *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	if req.GetBody == nil {
+		w.logf("request body: <not replayable, not logged>")
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		w.logf("request body: <%v>", err)
+		return
+	}
+	defer body.Close()
+
+	max := w.maxLogBody()
+	data, err := ioutil.ReadAll(io.LimitReader(body, int64(max)+1))
+	if err != nil {
+		w.logf("request body: <%v>", err)
+		return
+	}
+	w.logf("request body: %s", preview(data, max))
+
+	// A seekable body shares its reader with the copy, so take a fresh one.
+	if body, err = req.GetBody(); err == nil {
+		req.Body = body
+	}
+}
+
+// logDryRun logs req as it would be sent, regardless of LogHeaders and
+// LogBodies, to stdout if logging is off.
+func (w *WebClient) logDryRun(req *http.Request) {
+	if !w.logsEnabled() {
+		c := *w
+		c.logger = stdoutLogger{}
+		w = &c
+	}
+	w.applyDefaults(req)
+	w.logf("dry run: %s %s", req.Method, req.URL)
+	w.logHeaders(">", req.Header)
+	w.logRequestBody(req)
+}
+
+// logResponseBody logs a preview of an already read response body.
+func (w *WebClient) logResponseBody(data []byte) {
+	w.logf("response body: %s", preview(data, w.maxLogBody()))
+}
+
+func preview(data []byte, max int) string {
+	if len(data) > max {
+		return fmt.Sprintf("%q... (truncated)", data[:max])
+	}
+	return fmt.Sprintf("%q", data)
+}
@@ -0,0 +1,79 @@
+package brauser
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoggerFuncWithStdLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	o := DefaultOptions()
+	o.Logger = LoggerFunc(log.New(&buf, "", 0).Printf)
+	w := CreateWebClient(o)
+	if _, err := w.Get(srv.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET " + srv.URL; !strings.Contains(buf.String(), want) {
+		t.Errorf("log output %q doesn't contain %q", buf.String(), want)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry run sent %s %s", r.Method, r.URL)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	o := DefaultOptions()
+	o.DryRun = true
+	o.Logger = LoggerFunc(log.New(&buf, "", 0).Printf)
+	w := CreateWebClient(o)
+
+	resp, err := w.Send(context.Background(), "DELETE", srv.URL+"/items/1", Params{Header: map[string]string{"Authorization": "Bearer t"}}, strings.NewReader("payload"))
+	if err != nil || !resp.DryRun || resp.StatusCode != http.StatusOK {
+		t.Errorf("got %+v, %v, want a synthetic 200 marked DryRun", resp, err)
+	}
+	for _, want := range []string{"dry run: DELETE " + srv.URL + "/items/1", "Authorization: ***", `request body: "payload"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log output %q doesn't contain %q", buf.String(), want)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	if _, err := w.Do(req); err != ErrDryRun {
+		t.Errorf("Do: err = %v, want ErrDryRun", err)
+	}
+}
+
+func TestDryRunWithoutLogger(t *testing.T) {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = pw
+	defer func() { os.Stdout = stdout }()
+
+	o := DefaultOptions()
+	o.DryRun = true
+	w := CreateWebClient(o)
+	w.Get("http://example.invalid/", nil)
+	os.Stdout = stdout
+	pw.Close()
+
+	out, _ := ioutil.ReadAll(r)
+	if want := "dry run: GET http://example.invalid/"; !strings.Contains(string(out), want) {
+		t.Errorf("stdout %q doesn't contain %q", out, want)
+	}
+}
@@ -0,0 +1,38 @@
+package brauser
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics describes a finished request and is passed to
+// Options.OnComplete.
+type RequestMetrics struct {
+	Method     string
+	URL        string
+	StatusCode int // 0 if no response was received
+	Attempts   int
+	// Duration spans all attempts and backoff delays until the response
+	// was read or the request failed. For Do and the methods returning the
+	// body as a stream, it ends when the response headers arrived.
+	Duration time.Duration
+	Err      error
+	// Timings of the last attempt, only set with Options.TraceTimings.
+	Timings Timings
+}
+
+// report passes the outcome of req to Options.OnComplete.
+func (w *WebClient) report(req *http.Request, x exchange, status int, err error) {
+	if w.options.OnComplete == nil {
+		return
+	}
+	w.options.OnComplete(RequestMetrics{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: status,
+		Attempts:   x.attempts,
+		Duration:   w.clock.Now().Sub(x.start),
+		Err:        err,
+		Timings:    x.timings,
+	})
+}
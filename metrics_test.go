@@ -0,0 +1,107 @@
+package brauser
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// recordMetrics sets o.OnComplete to collect the metrics it is called with.
+func recordMetrics(o *Options) func() []RequestMetrics {
+	var mu sync.Mutex
+	var got []RequestMetrics
+	o.OnComplete = func(m RequestMetrics) {
+		mu.Lock()
+		got = append(got, m)
+		mu.Unlock()
+	}
+	return func() []RequestMetrics {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]RequestMetrics(nil), got...)
+	}
+}
+
+func TestOnCompleteAfterBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			rw.Write([]byte("0123456789"))
+			return
+		}
+		truncate(rw)
+	}))
+	defer srv.Close()
+
+	o := fastRetries(2)
+	o.RetryBodyErrors = true
+	o.MaxResponseBytes = 8
+	metrics := recordMetrics(&o)
+	w := CreateWebClient(o)
+
+	_, err := w.GetResponse(srv.URL+"/truncated", nil)
+	var be *BodyError
+	if !errors.As(err, &be) {
+		t.Fatalf("err = %v, want a *BodyError", err)
+	}
+	_, err = w.GetResponse(srv.URL+"/large", nil)
+	if err != ErrResponseTooLarge {
+		t.Fatalf("err = %v, want ErrResponseTooLarge", err)
+	}
+
+	got := metrics()
+	if len(got) != 2 {
+		t.Fatalf("OnComplete called %d times, want once per request", len(got))
+	}
+	if !errors.As(got[0].Err, &be) || got[0].Attempts != 2 {
+		t.Errorf("truncated body: Err = %v, Attempts = %d, want a *BodyError after 2 attempts", got[0].Err, got[0].Attempts)
+	}
+	if got[1].Err != ErrResponseTooLarge || got[1].StatusCode != http.StatusOK {
+		t.Errorf("large body: Err = %v, StatusCode = %d", got[1].Err, got[1].StatusCode)
+	}
+}
+
+func TestOnCompleteCountsReauth(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			rw.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	o := fastRetries(1)
+	o.OnUnauthorized = func() error { return nil }
+	metrics := recordMetrics(&o)
+	w := CreateWebClient(o)
+
+	resp, err := w.GetResponse(srv.URL, nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("got %d, %v", resp.StatusCode, err)
+	}
+	got := metrics()
+	if len(got) != 1 || got[0].Attempts != 2 || resp.Attempts != 2 {
+		t.Errorf("metrics %+v, Response.Attempts %d, want 2 attempts in both", got, resp.Attempts)
+	}
+}
+
+func TestOnCompleteCircuitOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	o := fastRetries(1)
+	o.BreakerThreshold = 1
+	metrics := recordMetrics(&o)
+	w := CreateWebClient(o)
+
+	w.Get(srv.URL, nil)
+	w.Get(srv.URL, nil)
+	got := metrics()
+	if len(got) != 2 || got[1].Err != ErrCircuitOpen || got[1].Attempts != 0 {
+		t.Errorf("metrics %+v, want the second request rejected without attempts", got)
+	}
+}
@@ -0,0 +1,33 @@
+package brauser
+
+import "net/http"
+
+// Middleware wraps a RoundTripper to add behaviour to every attempt of every
+// request, such as signing or tracing headers. Retries and logging happen
+// outside the middleware chain, so a middleware sees each attempt.
+//
+// A middleware adding a request ID could look like this:
+//
+//	func requestID(next http.RoundTripper) http.RoundTripper {
+//		return brauser.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//			req = req.Clone(req.Context())
+//			req.Header.Set("X-Request-Id", newID())
+//			return next.RoundTrip(req)
+//		})
+//	}
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// chain wraps rt in mws, with the first middleware being the outermost.
+func chain(rt http.RoundTripper, mws []Middleware) http.RoundTripper {
+	for i := len(mws) - 1; i >= 0; i-- {
+		rt = mws[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,82 @@
+// Package mock provides a fake brauser.Fetcher returning canned responses,
+// for testing code that uses brauser without a server.
+package mock
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/grzfrmbl/brauser"
+)
+
+// Call records a request made to a Fetcher.
+type Call struct {
+	Method string
+	Path   string
+	Params map[string]string
+	Body   []byte
+}
+
+type response struct {
+	body []byte
+	err  error
+}
+
+// Fetcher answers requests with the responses registered through Respond.
+// Requests without a registered response fail. It is safe for concurrent use.
+type Fetcher struct {
+	mu        sync.Mutex
+	responses map[string]response
+	calls     []Call
+}
+
+var _ brauser.Fetcher = (*Fetcher)(nil)
+
+// New returns a Fetcher without any responses.
+func New() *Fetcher {
+	return &Fetcher{responses: map[string]response{}}
+}
+
+// Respond makes requests for method and path return body and err.
+func (f *Fetcher) Respond(method, path string, body []byte, err error) *Fetcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method+" "+path] = response{body: body, err: err}
+	return f
+}
+
+// Calls returns the requests made so far, in order.
+func (f *Fetcher) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+func (f *Fetcher) Get(path string, params map[string]string) ([]byte, error) {
+	return f.CustomRequest("GET", path, params, nil)
+}
+
+func (f *Fetcher) Post(path string, params map[string]string, payload io.Reader) ([]byte, error) {
+	return f.CustomRequest("POST", path, params, payload)
+}
+
+func (f *Fetcher) CustomRequest(method, path string, params map[string]string, payload io.Reader) ([]byte, error) {
+	c := Call{Method: method, Path: path, Params: params}
+	if payload != nil {
+		var err error
+		if c.Body, err = ioutil.ReadAll(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, c)
+	r, ok := f.responses[method+" "+path]
+	if !ok {
+		return nil, fmt.Errorf("mock: no response for %s %s", method, path)
+	}
+	return r.body, r.err
+}
@@ -0,0 +1,137 @@
+package brauser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const netscapeHttpOnlyPrefix = "#HttpOnly_"
+
+// ExportCookiesNetscape writes the cookies for site to file in the Netscape
+// cookies.txt format understood by curl, wget and browser extensions.
+//
+// The jar only reveals cookie names and values, so every cookie is written as
+// a host-only session cookie for the site's host with path "/".
+func (w *WebClient) ExportCookiesNetscape(file, site string) error {
+	if w.cl.Jar == nil {
+		return ErrCookiesDisabled
+	}
+	u, err := url.Parse(site)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Netscape HTTP Cookie File\n")
+	for _, c := range w.cl.Jar.Cookies(u) {
+		writeNetscapeCookie(&buf, u.Hostname(), c)
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0644)
+}
+
+func writeNetscapeCookie(buf *bytes.Buffer, host string, c *http.Cookie) {
+	domain, sub := host, "FALSE"
+	if c.Domain != "" {
+		domain, sub = c.Domain, "TRUE"
+		if !strings.HasPrefix(domain, ".") {
+			domain = "." + domain
+		}
+	}
+	if c.HttpOnly {
+		domain = netscapeHttpOnlyPrefix + domain
+	}
+	path := c.Path
+	if path == "" {
+		path = "/"
+	}
+	var expires int64
+	if !c.Expires.IsZero() {
+		expires = c.Expires.Unix()
+	}
+	fmt.Fprintf(buf, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+		domain, sub, path, netscapeBool(c.Secure), expires, c.Name, c.Value)
+}
+
+// ImportCookiesNetscape loads a Netscape cookies.txt file into the jar.
+// Each cookie is stored for the domain recorded in the file.
+func (w *WebClient) ImportCookiesNetscape(file string) error {
+	if w.cl.Jar == nil {
+		return ErrCookiesDisabled
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		u, c, err := parseNetscapeLine(sc.Text())
+		if err != nil {
+			return fmt.Errorf("brauser: %s:%d: %w", file, n, err)
+		}
+		if c != nil {
+			w.cl.Jar.SetCookies(u, []*http.Cookie{c})
+		}
+	}
+	return sc.Err()
+}
+
+// parseNetscapeLine parses one line of a cookies.txt file. Blank lines and
+// comments yield a nil cookie.
+func parseNetscapeLine(line string) (*url.URL, *http.Cookie, error) {
+	line = strings.TrimRight(line, "\r")
+	c := &http.Cookie{}
+	if strings.HasPrefix(line, netscapeHttpOnlyPrefix) {
+		line = strings.TrimPrefix(line, netscapeHttpOnlyPrefix)
+		c.HttpOnly = true
+	} else if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+		return nil, nil, nil
+	}
+
+	fields := strings.Split(line, "\t")
+	if len(fields) != 7 {
+		return nil, nil, fmt.Errorf("expected 7 tab separated fields, got %d", len(fields))
+	}
+
+	domain := fields[0]
+	host := strings.TrimPrefix(domain, ".")
+	if host == "" {
+		return nil, nil, fmt.Errorf("empty domain")
+	}
+	if fields[1] == "TRUE" {
+		c.Domain = domain
+	}
+	c.Path = fields[2]
+	c.Secure = fields[3] == "TRUE"
+
+	expires, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid expiry %q", fields[4])
+	}
+	if expires > 0 {
+		c.Expires = time.Unix(expires, 0)
+	}
+	c.Name, c.Value = fields[5], fields[6]
+
+	scheme := "http"
+	if c.Secure {
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: host, Path: c.Path}, c, nil
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
@@ -0,0 +1,63 @@
+package brauser
+
+import "time"
+
+// Option changes a single setting for New. Any func(*Options) can be used as
+// an Option, so settings without a dedicated helper are still available:
+//
+//	brauser.New(brauser.WithTries(3), func(o *brauser.Options) { o.RateLimit = 5 })
+type Option func(*Options)
+
+// New creates a client starting from DefaultOptions and applying opts in
+// order. Settings not touched by an option keep their defaults.
+func New(opts ...Option) WebClient {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newWebClient(o)
+}
+
+// WithOptions replaces all settings with o.
+func WithOptions(o Options) Option {
+	return func(dst *Options) { *dst = o }
+}
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) { o.Timeout = d }
+}
+
+func WithDialTimeout(d time.Duration) Option {
+	return func(o *Options) { o.DialTimeout = d }
+}
+
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(o *Options) { o.TlsHandshakeTimeout = d }
+}
+
+func WithTries(n int) Option {
+	return func(o *Options) { o.Tries = n }
+}
+
+func WithRetryBackoff(base, max time.Duration) Option {
+	return func(o *Options) {
+		o.RetryBaseDelay = base
+		o.RetryMaxDelay = max
+	}
+}
+
+func WithVerbose(v bool) Option {
+	return func(o *Options) { o.Verbose = v }
+}
+
+func WithLogger(l Logger) Option {
+	return func(o *Options) { o.Logger = l }
+}
+
+func WithUserAgent(ua string) Option {
+	return func(o *Options) { o.UserAgent = ua }
+}
+
+func WithMiddleware(mws ...Middleware) Option {
+	return func(o *Options) { o.Middleware = append(o.Middleware, mws...) }
+}
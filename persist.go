@@ -0,0 +1,136 @@
+package brauser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cookieFlushDelay debounces writes of Options.CookieFile, so a burst of
+// responses setting cookies results in a single write.
+const cookieFlushDelay = time.Second
+
+// cookieFile keeps a jar in sync with a file on disk. The file is written
+// shortly after the jar changes and whenever flush is called.
+type cookieFile struct {
+	path string
+	jar  *cookieJar
+	logf func(format string, args ...interface{})
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// openCookieFile loads path into jar, if it exists, and starts tracking
+// changes to the jar.
+func openCookieFile(path string, jar *cookieJar, logf func(string, ...interface{})) (*cookieFile, error) {
+	f := &cookieFile{path: path, jar: jar, logf: logf}
+
+	data, err := readCookieFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		var entries []cookieEntry
+		if err = json.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+		if err = jar.restore(entries); err != nil {
+			return nil, err
+		}
+	}
+
+	jar.onChange = f.schedule
+	return f, nil
+}
+
+func (f *cookieFile) schedule() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.timer == nil {
+		f.timer = time.AfterFunc(cookieFlushDelay, func() {
+			if err := f.flush(); err != nil {
+				f.logf("saving cookies to %s: %v", f.path, err)
+			}
+		})
+	}
+}
+
+// flush writes the jar to disk now. The file is replaced atomically so a
+// crash can't leave a truncated file behind.
+func (f *cookieFile) flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+
+	data, err := json.Marshal(f.jar.all())
+	if err != nil {
+		return err
+	}
+	if data, err = encodeCookieFile(f.path, data); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// readCookieFile reads a cookie export, decompressing it if it starts with
+// the gzip magic number, which JSON never does.
+func readCookieFile(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil || !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ioutil.ReadAll(zr)
+}
+
+// writeCookieFile writes a cookie export, see encodeCookieFile.
+func writeCookieFile(file string, data []byte) error {
+	data, err := encodeCookieFile(file, data)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// encodeCookieFile gzips data if file is named "*.gz".
+func encodeCookieFile(file string, data []byte) ([]byte, error) {
+	if !strings.HasSuffix(file, ".gz") {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package brauser
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket allowing rate requests per second on average and
+// bursts of up to burst requests.
+type limiter struct {
+	rate  float64
+	burst float64
+	clock clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter returns nil, which means no limit, unless o.RateLimit is
+// positive.
+func newLimiter(o Options, clk clock) *limiter {
+	if o.RateLimit <= 0 {
+		return nil
+	}
+	burst := float64(o.RateBurst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{rate: o.RateLimit, burst: burst, clock: clk, tokens: burst, last: clk.Now()}
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+	if err := l.clock.Sleep(ctx, d); err != nil {
+		// Hand the reserved token back.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
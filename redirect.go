@@ -0,0 +1,59 @@
+package brauser
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultMaxRedirects = 10
+
+// RedirectError is returned when a request exceeds Options.MaxRedirects, or
+// runs into a loop with Options.DetectRedirectLoops. Chain lists every URL
+// visited, starting with the original request; for a loop it ends with the
+// URL seen before.
+type RedirectError struct {
+	Chain []string
+	Loop  bool
+}
+
+func (e *RedirectError) Error() string {
+	if e.Loop {
+		return fmt.Sprintf("brauser: redirect loop: %s", strings.Join(e.Chain, " -> "))
+	}
+	return fmt.Sprintf("brauser: stopped after %d redirects: %s", len(e.Chain)-1, strings.Join(e.Chain, " -> "))
+}
+
+// checkRedirect returns the http.Client.CheckRedirect policy for o.
+func checkRedirect(o Options) func(*http.Request, []*http.Request) error {
+	if o.DisableRedirects {
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	max := o.MaxRedirects
+	if max <= 0 {
+		max = defaultMaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		next := req.URL.String()
+		loop := false
+		if o.DetectRedirectLoops {
+			for _, r := range via {
+				if r.URL.String() == next {
+					loop = true
+					break
+				}
+			}
+		}
+		if !loop && len(via) < max {
+			return nil
+		}
+		chain := make([]string, 0, len(via)+1)
+		for _, r := range via {
+			chain = append(chain, r.URL.String())
+		}
+		return &RedirectError{Chain: append(chain, next), Loop: loop}
+	}
+}
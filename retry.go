@@ -0,0 +1,193 @@
+package brauser
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryStatusCodes are retried when Options.RetryStatusCodes is nil.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+	defaultMaxRetryAfter  = 2 * time.Minute
+)
+
+// tries returns the number of attempts to make per request.
+func (w *WebClient) tries() int {
+	if w.options.Tries < 1 {
+		return 1
+	}
+	return w.options.Tries
+}
+
+// backoff returns the delay to wait before retry number attempt (starting at
+// 0). The delay doubles with every attempt up to RetryMaxDelay, and a random
+// jitter of up to half the delay is applied so clients don't retry in lockstep.
+func (w *WebClient) backoff(attempt int) time.Duration {
+	base, max := w.options.RetryBaseDelay, w.options.RetryMaxDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+	if base > max {
+		base = max
+	}
+
+	d := max
+	if attempt < 32 {
+		if e := base << uint(attempt); e > 0 && e < max {
+			d = e
+		}
+	}
+
+	half := d / 2
+	return half + time.Duration(w.jitter(int64(d-half)+1))
+}
+
+// DefaultRetryIf is the retry decision used when Options.RetryStatusCodes and
+// Options.RetryIf are not set: retry transport errors, except for host names
+// that don't exist and redirect failures, and the statuses in
+// DefaultRetryStatusCodes. Custom predicates can fall back to it. Note that
+// it doesn't look at the request method, see Options.RetryNonIdempotent.
+func DefaultRetryIf(resp *http.Response, err error, attempt int) bool {
+	if err != nil {
+		return !permanent(err)
+	}
+	return containsCode(DefaultRetryStatusCodes, resp.StatusCode)
+}
+
+// shouldRetry decides whether the outcome of attempt number attempt warrants
+// another one, disregarding the attempt limit.
+func (w *WebClient) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if w.options.RetryIf != nil {
+		return w.options.RetryIf(resp, err, attempt)
+	}
+	if !w.options.RetryNonIdempotent && !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		return !permanent(err)
+	}
+	return w.retryStatus(resp.StatusCode)
+}
+
+// retryBody reports whether a request whose response body couldn't be read
+// because of err should be sent again, disregarding the attempt limit.
+func (w *WebClient) retryBody(req *http.Request, err error) bool {
+	var be *BodyError
+	if !w.options.RetryBodyErrors || !errors.As(err, &be) {
+		return false
+	}
+	if !w.options.RetryNonIdempotent && !idempotent(req) || !replayable(req) {
+		return false
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// permanent reports whether a transport error would only happen again on a
+// retry.
+func permanent(err error) bool {
+	var re *RedirectError
+	return notFound(err) || errors.As(err, &re)
+}
+
+// idempotent reports whether sending req twice has the same effect as sending
+// it once: either because of its method or because it carries an
+// Idempotency-Key the server can deduplicate on.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		// Fall back to math/rand rather than sending no key.
+		rand.Read(b[:])
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// retryStatus reports whether a response with the given status code should be
+// retried.
+func (w *WebClient) retryStatus(code int) bool {
+	codes := w.options.RetryStatusCodes
+	if codes == nil {
+		codes = DefaultRetryStatusCodes
+	}
+	return containsCode(codes, code)
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// drainBody reads what is left of a body we are not interested in and closes
+// it, so the underlying connection can be reused.
+func drainBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
+// retryAfter returns the wait requested by the Retry-After header of a 429 or
+// 503 response, clamped to MaxRetryAfter. The header may either hold a number
+// of seconds or an HTTP date.
+func (w *WebClient) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(w.clock.Now())
+	} else {
+		return 0, false
+	}
+
+	if d < 0 {
+		d = 0
+	}
+	max := w.options.MaxRetryAfter
+	if max <= 0 {
+		max = defaultMaxRetryAfter
+	}
+	if d > max {
+		d = max
+	}
+	return d, true
+}
@@ -0,0 +1,270 @@
+package brauser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries returns the default options with retry delays short enough
+// for tests.
+func fastRetries(tries int) Options {
+	o := DefaultOptions()
+	o.Tries = tries
+	o.RetryBaseDelay = time.Millisecond
+	o.RetryMaxDelay = 5 * time.Millisecond
+	return o
+}
+
+// truncate answers with a Content-Length larger than the body it sends, so
+// the client sees the connection close mid-body.
+func truncate(rw http.ResponseWriter) {
+	rw.Header().Set("Content-Length", "100")
+	rw.Write([]byte("partial"))
+}
+
+func TestRetryBodyErrorsCountAgainstTries(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		truncate(rw)
+	}))
+	defer srv.Close()
+
+	o := fastRetries(3)
+	o.RetryBodyErrors = true
+	w := CreateWebClient(o)
+	resp, err := w.GetResponse(srv.URL, nil)
+
+	var be *BodyError
+	if !errors.As(err, &be) {
+		t.Fatalf("err = %v, want a *BodyError", err)
+	}
+	if got := atomic.LoadInt32(&n); got != 3 {
+		t.Errorf("server got %d requests, want 3", got)
+	}
+	if resp.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", resp.Attempts)
+	}
+	if string(resp.Body) != "partial" {
+		t.Errorf("Body = %q, want the bytes read before the failure", resp.Body)
+	}
+}
+
+func TestRetryBodyErrorsMidBody(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&n, 1) == 1 {
+			truncate(rw)
+			return
+		}
+		rw.Write([]byte("complete"))
+	}))
+	defer srv.Close()
+
+	for _, retry := range []bool{false, true} {
+		atomic.StoreInt32(&n, 0)
+		o := fastRetries(2)
+		o.RetryBodyErrors = retry
+		w := CreateWebClient(o)
+		resp, err := w.GetResponse(srv.URL, nil)
+
+		if !retry {
+			var be *BodyError
+			if !errors.As(err, &be) || be.Read != 7 || be.Expected != 100 {
+				t.Errorf("without RetryBodyErrors: err = %v, want a *BodyError after 7 of 100 bytes", err)
+			}
+			continue
+		}
+		if err != nil || string(resp.Body) != "complete" {
+			t.Errorf("with RetryBodyErrors: got %q, %v, want the complete body", resp.Body, err)
+		}
+		if resp.Attempts != 2 {
+			t.Errorf("with RetryBodyErrors: Attempts = %d, want 2", resp.Attempts)
+		}
+	}
+}
+
+func TestRetryBodyErrorsWithinTotalTimeout(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		truncate(rw)
+	}))
+	defer srv.Close()
+
+	o := fastRetries(100)
+	o.RetryBaseDelay = 40 * time.Millisecond
+	o.RetryMaxDelay = 40 * time.Millisecond
+	o.TotalTimeout = 100 * time.Millisecond
+	o.RetryBodyErrors = true
+	w := CreateWebClient(o)
+
+	start := time.Now()
+	_, err := w.GetResponse(srv.URL, nil)
+	if !errors.Is(err, ErrTotalTimeout) {
+		t.Errorf("err = %v, want ErrTotalTimeout", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("request took %v, TotalTimeout didn't bound the body retries", d)
+	}
+	if got := atomic.LoadInt32(&n); got > 4 {
+		t.Errorf("server got %d requests within a budget for at most 4", got)
+	}
+}
+
+func TestRetriesExhaustedKeepBody(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte("down"))
+	}))
+	defer srv.Close()
+
+	w := CreateWebClient(fastRetries(3))
+	resp, err := w.GetResponse(srv.URL, nil)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || string(resp.Body) != "down" {
+		t.Errorf("got %d %q, %v, want the last 503 with its body", resp.StatusCode, resp.Body, err)
+	}
+	if got := atomic.LoadInt32(&n); got != 3 || resp.Attempts != 3 {
+		t.Errorf("server got %d requests, Attempts = %d, want 3", got, resp.Attempts)
+	}
+}
+
+func TestBackoffGrowsUpToCap(t *testing.T) {
+	o := DefaultOptions()
+	o.RetryBaseDelay = 100 * time.Millisecond
+	o.RetryMaxDelay = 2 * time.Second
+	w := CreateWebClient(o)
+
+	for i := 0; i < 100; i++ {
+		prev := time.Duration(0)
+		for attempt := 0; attempt < 40; attempt++ {
+			d := w.backoff(attempt)
+			if d > o.RetryMaxDelay {
+				t.Fatalf("backoff(%d) = %v, above RetryMaxDelay %v", attempt, d, o.RetryMaxDelay)
+			}
+			want := o.RetryBaseDelay << uint(attempt)
+			if attempt >= 5 {
+				want = o.RetryMaxDelay
+			}
+			if d < want/2 || d > want {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", attempt, d, want/2, want)
+			}
+			if attempt < 5 && d < prev {
+				t.Fatalf("backoff(%d) = %v, shorter than backoff(%d) = %v", attempt, d, attempt-1, prev)
+			}
+			prev = d
+		}
+	}
+}
+
+func TestTries(t *testing.T) {
+	var n int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	// Zero is filled in with the default of 2, like the other zero options.
+	for _, tt := range []struct{ tries, want int }{{-1, 1}, {0, 2}, {1, 1}, {3, 3}} {
+		atomic.StoreInt32(&n, 0)
+		w := CreateWebClient(fastRetries(tt.tries))
+		resp, err := w.GetResponse(srv.URL, nil)
+		if err != nil || resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("Tries %d: got %d, %v", tt.tries, resp.StatusCode, err)
+		}
+		if got := int(atomic.LoadInt32(&n)); got != tt.want || resp.Attempts != tt.want {
+			t.Errorf("Tries %d: server got %d requests, Attempts = %d, want %d", tt.tries, got, resp.Attempts, tt.want)
+		}
+	}
+}
+
+func TestRetryByMethod(t *testing.T) {
+	var (
+		n    int32
+		keys = make(chan string, 10)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&n, 1)
+		if k := r.Header.Get("Idempotency-Key"); k != "" {
+			keys <- k
+		}
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		method        string
+		header        map[string]string
+		auto, nonIdem bool
+		want          int32
+	}{
+		{method: "GET", want: 2},
+		{method: "HEAD", want: 2},
+		{method: "PUT", want: 2},
+		{method: "DELETE", want: 2},
+		{method: "OPTIONS", want: 2},
+		{method: "POST", want: 1},
+		{method: "PATCH", want: 1},
+		{method: "POST", header: map[string]string{"Idempotency-Key": "k1"}, want: 2},
+		{method: "PATCH", auto: true, want: 2},
+		{method: "POST", nonIdem: true, want: 2},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&n, 0)
+		o := fastRetries(2)
+		o.AutoIdempotencyKey = tt.auto
+		o.RetryNonIdempotent = tt.nonIdem
+		w := CreateWebClient(o)
+		if _, err := w.CustomRequestResponse(tt.method, srv.URL, tt.header, nil); err != nil {
+			t.Fatalf("%s: %v", tt.method, err)
+		}
+		if got := atomic.LoadInt32(&n); got != tt.want {
+			t.Errorf("%s (header %v, auto key %v, RetryNonIdempotent %v): %d attempts, want %d", tt.method, tt.header, tt.auto, tt.nonIdem, got, tt.want)
+		}
+
+		var sent []string
+		for len(keys) > 0 {
+			sent = append(sent, <-keys)
+		}
+		if (tt.auto || tt.header != nil) && (len(sent) != 2 || sent[0] != sent[1] || sent[0] == "") {
+			t.Errorf("%s: sent Idempotency-Keys %q, want the same key on both attempts", tt.method, sent)
+		}
+	}
+}
+
+func TestBodyErrorCompressed(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(bytes.Repeat([]byte("brauser "), 1000))
+	zw.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Encoding", "gzip")
+		rw.Header().Set("Content-Length", fmt.Sprint(gz.Len()+100))
+		rw.Write(gz.Bytes())
+	}))
+	defer srv.Close()
+
+	w := CreateWebClient(fastRetries(1))
+	_, err := w.GetResponse(srv.URL, map[string]string{"Accept-Encoding": "gzip"})
+	var be *BodyError
+	if !errors.As(err, &be) {
+		t.Fatalf("err = %v, want a *BodyError", err)
+	}
+	if be.Read != int64(gz.Len()) || be.Expected != int64(gz.Len()+100) {
+		t.Errorf("got %d of %d bytes, want %d of %d as sent by the server", be.Read, be.Expected, gz.Len(), gz.Len()+100)
+	}
+}
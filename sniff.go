@@ -0,0 +1,62 @@
+package brauser
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path/filepath"
+)
+
+// sniffLen is the number of bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+// detectContentType guesses the media type of a body starting with head.
+// When the content gives no hint, the extension of name is consulted.
+func detectContentType(name string, head []byte) string {
+	ct := http.DetectContentType(head)
+	if ct == "application/octet-stream" && name != "" {
+		if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+			return t
+		}
+	}
+	return ct
+}
+
+// sniffContentType sets the Content-Type of req from the start of its body,
+// see Options.SniffContentType. Replayable bodies are read through GetBody
+// and reset afterwards; others are read ahead and stitched back together.
+func sniffContentType(req *http.Request, name string) error {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Type") != "" {
+		return nil
+	}
+
+	var head []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		head, err = ioutil.ReadAll(io.LimitReader(rc, sniffLen))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if req.Body, err = req.GetBody(); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		head, err = ioutil.ReadAll(io.LimitReader(req.Body, sniffLen))
+		if err != nil {
+			return err
+		}
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+	}
+	req.Header.Set("Content-Type", detectContentType(name, head))
+	return nil
+}
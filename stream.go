@@ -0,0 +1,108 @@
+package brauser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Stream issues a GET request for path and calls handler for every line of
+// the response body as it arrives, e.g. for server-sent events or JSON lines.
+// The line passed to handler excludes the line ending and is only valid until
+// handler returns. Streaming stops when the body ends, which returns nil, or
+// when handler returns an error, which is returned as is.
+//
+// Options.Timeout does not apply to streams since they may stay open
+// indefinitely; use StreamWithContext to stop them.
+func (w *WebClient) Stream(path string, params map[string]string, handler func(line []byte) error) error {
+	return w.StreamWithContext(context.Background(), path, params, handler)
+}
+
+// StreamWithContext is like Stream, cancelling ctx closes the stream.
+func (w *WebClient) StreamWithContext(ctx context.Context, path string, params map[string]string, handler func(line []byte) error) error {
+	req, err := w.newRequest(request{ctx: ctx, method: "GET", path: path, header: params})
+	if err != nil {
+		return err
+	}
+	resp, err := w.do(req, -1)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, dec, err := w.responseBody(resp)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	if w.options.ErrorOnStatus && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		data, _ := ioutil.ReadAll(io.LimitReader(body, 64<<10))
+		return &HTTPStatusError{Code: resp.StatusCode, Body: data}
+	}
+
+	br := bufio.NewReader(body)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if herr := handler(bytes.TrimRight(line, "\r\n")); herr != nil {
+				return herr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return classify(err)
+		}
+	}
+}
+
+// GetReader issues a GET request for path and returns the response body as a
+// stream, decompressed like the bodies returned by Get, along with the
+// response for its status and headers. Retries happen before GetReader
+// returns; failures while reading the stream are not retried. The caller must
+// Close the reader. Options.Timeout covers reading the body as well.
+func (w *WebClient) GetReader(path string, params map[string]string) (io.ReadCloser, *http.Response, error) {
+	req, err := w.newRequest(request{ctx: context.Background(), method: "GET", path: path, header: params})
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := w.do(req, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, dec, err := w.responseBody(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, nil, err
+	}
+	rc := &streamBody{Reader: body, dec: dec, body: resp.Body}
+
+	if w.options.ErrorOnStatus && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		data, _ := ioutil.ReadAll(io.LimitReader(rc, 64<<10))
+		rc.Close()
+		return nil, resp, &HTTPStatusError{Code: resp.StatusCode, Body: data}
+	}
+	return rc, resp, nil
+}
+
+// streamBody is a decompressed response body that closes both the decoder
+// and the underlying body.
+type streamBody struct {
+	io.Reader
+	dec  io.Closer
+	body io.Closer
+}
+
+func (b *streamBody) Close() error {
+	b.dec.Close()
+	return b.body.Close()
+}
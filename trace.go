@@ -0,0 +1,87 @@
+package brauser
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings breaks an attempt down into its phases, see Options.TraceTimings.
+// DNS, Connect and TLS are zero when an idle connection was reused.
+type Timings struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// FirstByte is the time from writing the request until the first byte
+	// of the response arrived, i.e. mostly time spent by the server.
+	FirstByte time.Duration
+	Reused    bool
+}
+
+func (t Timings) String() string {
+	if t.Reused {
+		return fmt.Sprintf("reused connection, first byte %v", t.FirstByte)
+	}
+	return fmt.Sprintf("new connection, dns %v, connect %v, tls %v, first byte %v", t.DNS, t.Connect, t.TLS, t.FirstByte)
+}
+
+// tracer collects Timings for one attempt. The hooks can run on different
+// goroutines, e.g. when several addresses are dialed in parallel.
+type tracer struct {
+	mu                                      sync.Mutex
+	t                                       Timings
+	dnsStart, connectStart, tlsStart, wrote time.Time
+}
+
+func (tr *tracer) clientTrace() *httptrace.ClientTrace {
+	since := func(start *time.Time, d *time.Duration) {
+		tr.mu.Lock()
+		if !start.IsZero() {
+			*d = time.Since(*start)
+		}
+		tr.mu.Unlock()
+	}
+	mark := func(t *time.Time) {
+		tr.mu.Lock()
+		*t = time.Now()
+		tr.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			tr.mu.Lock()
+			tr.t.Reused = info.Reused
+			tr.mu.Unlock()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&tr.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { since(&tr.dnsStart, &tr.t.DNS) },
+		ConnectStart:         func(_, _ string) { mark(&tr.connectStart) },
+		ConnectDone:          func(_, _ string, _ error) { since(&tr.connectStart, &tr.t.Connect) },
+		TLSHandshakeStart:    func() { mark(&tr.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { since(&tr.tlsStart, &tr.t.TLS) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&tr.wrote) },
+		GotFirstResponseByte: func() { since(&tr.wrote, &tr.t.FirstByte) },
+	}
+}
+
+// connTrace logs whether an attempt of req got an idle connection from the
+// pool or had to open a new one, which points at keep-alive problems.
+func (w *WebClient) connTrace(req *http.Request) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				w.logf("%s %s: reused connection (idle %v)", req.Method, req.URL, info.IdleTime)
+			} else {
+				w.logf("%s %s: new connection to %v", req.Method, req.URL, info.Conn.RemoteAddr())
+			}
+		},
+	}
+}
+
+func (tr *tracer) timings() Timings {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.t
+}
@@ -0,0 +1,50 @@
+package brauser
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// traffic counts the body bytes sent and received by a client and its copies.
+type traffic struct {
+	sent, received int64
+}
+
+// BytesSent returns the number of request body bytes sent by the client,
+// including resent bodies of retried requests.
+func (w *WebClient) BytesSent() int64 {
+	return atomic.LoadInt64(&w.traffic.sent)
+}
+
+// BytesReceived returns the number of response body bytes read from the
+// network, before decompression. Headers are not counted.
+func (w *WebClient) BytesReceived() int64 {
+	return atomic.LoadInt64(&w.traffic.received)
+}
+
+// meterRequest counts what is read from the body of req.
+func (t *traffic) meterRequest(req *http.Request) {
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &meteredBody{ReadCloser: req.Body, n: &t.sent}
+	}
+}
+
+// meterResponse counts what is read from the body of resp.
+func (t *traffic) meterResponse(resp *http.Response) {
+	if resp != nil {
+		resp.Body = &meteredBody{ReadCloser: resp.Body, n: &t.received}
+	}
+}
+
+// meteredBody adds the bytes read from a body to a shared counter.
+type meteredBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(b.n, int64(n))
+	return n, err
+}
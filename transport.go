@@ -0,0 +1,105 @@
+package brauser
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// newTransport builds the transport used by CreateWebClient from o.
+func newTransport(o Options) *http.Transport {
+	proxy := o.Proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+	if o.ProxyUsername != "" {
+		proxy = proxyWithAuth(proxy, url.UserPassword(o.ProxyUsername, o.ProxyPassword))
+	}
+
+	// DialContext, unlike Dial, lets a cancelled request context abort a
+	// pending connect.
+	dialer := &net.Dialer{
+		Timeout:   timeout(o.DialTimeout),
+		KeepAlive: o.KeepAlive,
+		Resolver:  o.Resolver,
+	}
+
+	dial := dialer.DialContext
+	if o.UnixSocket != "" {
+		// Every connection goes to the socket, whatever host the URL names.
+		dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", o.UnixSocket)
+		}
+		proxy = nil
+	}
+
+	t := &http.Transport{
+		DialContext:           dial,
+		TLSHandshakeTimeout:   timeout(o.TlsHandshakeTimeout),
+		TLSClientConfig:       tlsConfig(o),
+		Proxy:                 proxy,
+		MaxIdleConns:          o.MaxIdleConns,
+		MaxIdleConnsPerHost:   o.MaxIdleConnsPerHost,
+		IdleConnTimeout:       timeout(o.IdleConnTimeout),
+		ExpectContinueTimeout: timeout(o.ExpectContinueTimeout),
+	}
+
+	// A custom dialer or TLS config keeps the transport from negotiating
+	// HTTP/2 unless asked to explicitly, and a non-nil TLSNextProto map turns
+	// it off for good.
+	if o.DisableHTTP2 {
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		t.ForceAttemptHTTP2 = true
+	}
+	return t
+}
+
+// proxyWithAuth adds user to the proxy URLs returned by proxy that don't
+// carry credentials already. The transport turns them into a
+// Proxy-Authorization header, both for CONNECT and plain HTTP requests.
+func proxyWithAuth(proxy func(*http.Request) (*url.URL, error), user *url.Userinfo) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		u, err := proxy(req)
+		if err != nil || u == nil || u.User != nil {
+			return u, err
+		}
+		uu := *u
+		uu.User = user
+		return &uu, nil
+	}
+}
+
+// tlsConfig returns the TLS settings requested by o, or nil to use Go's
+// defaults.
+func tlsConfig(o Options) *tls.Config {
+	if !o.InsecureSkipVerify && o.RootCAs == nil && len(o.ClientCertificates) == 0 && o.MinTLSVersion == 0 && o.CipherSuites == nil {
+		return nil
+	}
+	return &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		RootCAs:            o.RootCAs,
+		Certificates:       o.ClientCertificates,
+		MinVersion:         o.MinTLSVersion,
+		CipherSuites:       o.CipherSuites,
+	}
+}
+
+// LoadCertPool reads PEM encoded certificates from file into a new pool,
+// suitable for Options.RootCAs.
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("brauser: no certificates found in " + file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,274 @@
+package brauser
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newProxy starts a plain HTTP proxy that answers requests itself instead of
+// forwarding them, echoing the requested URL. If auth is not empty, requests
+// without a matching Proxy-Authorization header get a 407.
+func newProxy(auth string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if auth != "" && r.Header.Get("Proxy-Authorization") != auth {
+			rw.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		rw.Write([]byte("proxied " + r.URL.String()))
+	}))
+}
+
+func TestProxyAuth(t *testing.T) {
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	proxy := newProxy(auth)
+	defer proxy.Close()
+	pu, _ := url.Parse(proxy.URL)
+
+	for _, password := range []string{"secret", "wrong"} {
+		o := DefaultOptions()
+		o.Proxy = http.ProxyURL(pu)
+		o.ProxyUsername = "user"
+		o.ProxyPassword = password
+		w := CreateWebClient(o)
+
+		resp, err := w.GetResponse("http://example.invalid/page", nil)
+		if err != nil {
+			t.Fatalf("password %q: %v", password, err)
+		}
+		want := http.StatusOK
+		if password != "secret" {
+			want = http.StatusProxyAuthRequired
+		}
+		if resp.StatusCode != want {
+			t.Errorf("password %q: status %d, want %d", password, resp.StatusCode, want)
+		}
+		if want == http.StatusOK && string(resp.Body) != "proxied http://example.invalid/page" {
+			t.Errorf("password %q: body %q", password, resp.Body)
+		}
+	}
+}
+
+// trusting returns the default options with srv's certificate as the only
+// trusted root.
+func trusting(srv *httptest.Server) Options {
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	o := DefaultOptions()
+	o.RootCAs = pool
+	return o
+}
+
+func TestHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.Proto))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, disable := range []bool{false, true} {
+		o := trusting(srv)
+		o.DisableHTTP2 = disable
+		w := CreateWebClient(o)
+		body, err := w.Get(srv.URL, nil)
+		if err != nil {
+			t.Fatalf("DisableHTTP2 %v: %v", disable, err)
+		}
+		want := "HTTP/2.0"
+		if disable {
+			want = "HTTP/1.1"
+		}
+		if string(body) != want {
+			t.Errorf("DisableHTTP2 %v: negotiated %s, want %s", disable, body, want)
+		}
+	}
+}
+
+func TestProxy(t *testing.T) {
+	proxy := newProxy("")
+	defer proxy.Close()
+	pu, _ := url.Parse(proxy.URL)
+
+	o := DefaultOptions()
+	o.Proxy = http.ProxyURL(pu)
+	w := CreateWebClient(o)
+	body, err := w.Get("http://example.invalid/path?q=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "proxied http://example.invalid/path?q=1"; string(body) != want {
+		t.Errorf("got %q, want %q", body, want)
+	}
+}
+
+// clientCert returns a self-signed certificate for client authentication.
+func clientCert(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "brauser test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, cert
+}
+
+func TestClientCertificates(t *testing.T) {
+	cert, leaf := clientCert(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	// The handshake failure without a certificate is logged by the server.
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	o := trusting(srv)
+	o.Tries = 1
+	w := CreateWebClient(o)
+	if _, err := w.Get(srv.URL, nil); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+
+	o.ClientCertificates = []tls.Certificate{cert}
+	w = CreateWebClient(o)
+	body, err := w.Get(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "brauser test client" {
+		t.Errorf("server saw client %q", body)
+	}
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+func TestExpectContinueRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Answering without reading the body withholds the 100 Continue.
+		rw.WriteHeader(http.StatusExpectationFailed)
+	}))
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.ExpectContinue = true
+	o.ExpectContinueTimeout = 5 * time.Second
+	w := CreateWebClient(o)
+
+	const size = 1 << 20
+	body := &countingReader{r: bytes.NewReader(make([]byte, size))}
+	start := time.Now()
+	resp, err := w.PostResponse(srv.URL, nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusExpectationFailed {
+		t.Errorf("status %d, want 417", resp.StatusCode)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("took %v, the client waited out ExpectContinueTimeout", d)
+	}
+	// The transport may probe a byte to see whether the body is empty.
+	if n := atomic.LoadInt64(&body.n); n > 64<<10 {
+		t.Errorf("sent %d of %d body bytes after the server refused them", n, size)
+	}
+}
+
+func TestMinTLSVersion(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS10}
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		min uint16
+		ok  bool
+	}{{tls.VersionTLS10, true}, {tls.VersionTLS12, false}} {
+		o := trusting(srv)
+		o.Tries = 1
+		o.MinTLSVersion = tt.min
+		w := CreateWebClient(o)
+		_, err := w.Get(srv.URL, nil)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("MinTLSVersion %#x against a TLS 1.0 server: err = %v", tt.min, err)
+		}
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "brauser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "daemon.sock")
+
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(r.Host + r.URL.Path))
+	}))
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+
+	o := DefaultOptions()
+	o.UnixSocket = sock
+	w := CreateWebClient(o)
+	body, err := w.Get("http://docker/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "docker/version" {
+		t.Errorf("got %q, want the request for http://docker/version", body)
+	}
+}
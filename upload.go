@@ -0,0 +1,31 @@
+package brauser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+)
+
+// PostGzip gzip-compresses payload and posts it with Content-Encoding: gzip,
+// for servers that accept compressed request bodies. The compressed body is
+// held in memory so retries can resend it.
+func (w *WebClient) PostGzip(path string, payload io.Reader) (Response, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, payload); err != nil {
+		return Response{}, fmt.Errorf("brauser: compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return Response{}, fmt.Errorf("brauser: compress request body: %w", err)
+	}
+
+	return w.fetch(request{
+		ctx:    context.Background(),
+		method: "POST",
+		path:   path,
+		header: map[string]string{"Content-Encoding": "gzip"},
+		body:   bytes.NewReader(buf.Bytes()),
+	})
+}